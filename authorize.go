@@ -1,7 +1,9 @@
 package pocketbase
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -15,15 +17,65 @@ type authStore interface {
 }
 
 type authorizer interface {
-	authorize() error
+	// authorize refreshes the token when needed, sending the request with ctx of the call that triggered it.
+	authorize(ctx context.Context) error
+	onChange(fn func(token string)) (remove func())
+}
+
+// authListeners notifies subscribers (e.g. realtime streams) about token refreshes.
+type authListeners struct {
+	mu   sync.Mutex
+	next int
+	fns  map[int]func(token string)
+}
+
+func (l *authListeners) onChange(fn func(token string)) func() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.fns == nil {
+		l.fns = map[int]func(token string){}
+	}
+	id := l.next
+	l.next++
+	l.fns[id] = fn
+
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.fns, id)
+	}
+}
+
+func (l *authListeners) notify(token string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, fn := range l.fns {
+		// listeners may call authorize again, so they can't run inside singleflight
+		go fn(token)
+	}
+}
+
+// awaitAuth waits for a token refresh shared by concurrent callers. The refresh keeps the values of the
+// first caller's ctx, like its trace, but not its cancellation, so one caller giving up doesn't fail the others.
+func awaitAuth(ctx context.Context, results <-chan singleflight.Result) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case result := <-results:
+		return result.Err
+	}
 }
 
 type authorizeNoOp struct{}
 
-func (a authorizeNoOp) authorize() error {
+func (a authorizeNoOp) authorize(context.Context) error {
 	return nil
 }
 
+func (a authorizeNoOp) onChange(func(token string)) func() {
+	return func() {}
+}
+
 func (a authorizeNoOp) IsValid() bool {
 	return false
 }
@@ -33,13 +85,16 @@ func (a authorizeNoOp) Token() string {
 }
 
 type authorizeEmailPassword struct {
+	authListeners
 	email       string
 	password    string
-	token       string
-	tokenValid  time.Time
 	client      *resty.Client
 	url         string
 	tokenSingle singleflight.Group
+
+	mu         sync.RWMutex
+	token      string
+	tokenValid time.Time
 }
 
 func newAuthorizeEmailPassword(c *resty.Client, url string, email string, password string) authStore {
@@ -52,17 +107,21 @@ func newAuthorizeEmailPassword(c *resty.Client, url string, email string, passwo
 	}
 }
 
-func (a *authorizeEmailPassword) authorize() error {
+func (a *authorizeEmailPassword) authorize(ctx context.Context) error {
 	type authResponse struct {
 		Token string `json:"token"`
 	}
 
-	_, err, _ := a.tokenSingle.Do("auth", func() (interface{}, error) {
-		if time.Now().Before(a.tokenValid) {
+	if a.IsValid() {
+		return nil
+	}
+	results := a.tokenSingle.DoChan("auth", func() (interface{}, error) {
+		if a.IsValid() {
 			return nil, nil
 		}
 
 		resp, err := a.client.R().
+			SetContext(withOperation(context.WithoutCancel(ctx), Operation{Name: OperationAuth})).
 			SetHeader("Content-Type", "application/json").
 			SetBody(map[string]interface{}{
 				"identity": a.email,
@@ -85,19 +144,26 @@ func (a *authorizeEmailPassword) authorize() error {
 		}
 
 		auth := *resp.Result().(*authResponse)
+		a.mu.Lock()
 		a.token = auth.Token
-		a.client.SetHeader("Authorization", auth.Token)
 		a.tokenValid = time.Now().Add(60 * time.Minute)
+		a.mu.Unlock()
+		a.client.SetHeader("Authorization", auth.Token)
+		a.notify(auth.Token)
 
 		return nil, nil
 	})
-	return err
+	return awaitAuth(ctx, results)
 }
 
 func (a *authorizeEmailPassword) IsValid() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return time.Now().Before(a.tokenValid)
 }
 
 func (a *authorizeEmailPassword) Token() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.token
 }
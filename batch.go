@@ -0,0 +1,161 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+var ErrBatchNotSupported = errors.New("batch api is not supported by the server")
+
+// Batch queues record operations across collections and executes them
+// atomically in a single /api/batch request (PocketBase v0.23+).
+type Batch struct {
+	client *Client
+
+	mu       sync.Mutex
+	requests []batchRequest
+	decoders []func(status int, body json.RawMessage) error
+}
+
+type batchRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   any    `json:"body,omitempty"`
+}
+
+type BatchResult[T any] struct {
+	Status int
+	Record T
+	Raw    json.RawMessage
+}
+
+// BatchCollection queues typed operations of a collection into a Batch.
+type BatchCollection[T any] struct {
+	batch *Batch
+	name  string
+}
+
+func (c *Client) NewBatch() *Batch {
+	return &Batch{client: c}
+}
+
+func (c Collection[T]) InBatch(b *Batch) BatchCollection[T] {
+	return BatchCollection[T]{batch: b, name: c.Name}
+}
+
+// Create queues a record creation, the result is filled in by Batch.Send.
+func (c BatchCollection[T]) Create(body T) *BatchResult[T] {
+	return queueBatch[T](c.batch, http.MethodPost, c.recordsURL(""), body)
+}
+
+// Upsert queues an update of the record with the id from body, or its creation if it doesn't exist.
+func (c BatchCollection[T]) Upsert(body T) *BatchResult[T] {
+	return queueBatch[T](c.batch, http.MethodPut, c.recordsURL(""), body)
+}
+
+func (c BatchCollection[T]) Update(id string, body any) *BatchResult[T] {
+	return queueBatch[T](c.batch, http.MethodPatch, c.recordsURL(id), body)
+}
+
+func (c BatchCollection[T]) Delete(id string) *BatchResult[T] {
+	return queueBatch[T](c.batch, http.MethodDelete, c.recordsURL(id), nil)
+}
+
+func (c BatchCollection[T]) recordsURL(id string) string {
+	u := "/api/collections/" + url.PathEscape(c.name) + "/records"
+	if id != "" {
+		u += "/" + url.PathEscape(id)
+	}
+	return u
+}
+
+func queueBatch[T any](b *Batch, method, path string, body any) *BatchResult[T] {
+	result := &BatchResult[T]{}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests = append(b.requests, batchRequest{Method: method, URL: path, Body: body})
+	b.decoders = append(b.decoders, func(status int, body json.RawMessage) error {
+		result.Status = status
+		result.Raw = body
+		if len(body) == 0 || status == http.StatusNoContent {
+			return nil
+		}
+		return b.client.decode(body, &result.Record)
+	})
+	return result
+}
+
+func (b *Batch) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.requests)
+}
+
+// Send executes all queued operations in one transaction and fills their results.
+// Once the server applied them the batch is emptied, so it can queue the next operations.
+// Servers without the batch endpoint return an error wrapping ErrBatchNotSupported.
+func (b *Batch) Send() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.requests) == 0 {
+		return nil
+	}
+
+	if err := b.client.Authorize(); err != nil {
+		return err
+	}
+
+	request := b.client.request(Operation{Name: OperationBatch}).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{"requests": b.requests})
+
+	resp, err := request.Post(b.client.url + "/api/batch")
+	if err != nil {
+		return fmt.Errorf("[batch] can't send batch request to pocketbase, err %w", err)
+	}
+
+	if code := resp.StatusCode(); code == http.StatusNotFound || code == http.StatusMethodNotAllowed {
+		return fmt.Errorf("[batch] pocketbase returned status: %d, err %w", code, ErrBatchNotSupported)
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("[batch] pocketbase returned status: %d, msg: %s, err %w",
+			resp.StatusCode(),
+			resp.String(),
+			ErrInvalidResponse,
+		)
+	}
+
+	var results []struct {
+		Status int             `json:"status"`
+		Body   json.RawMessage `json:"body"`
+	}
+	if err := b.client.codec.Unmarshal(resp.Body(), &results); err != nil {
+		return fmt.Errorf("[batch] can't unmarshal response, err %w", err)
+	}
+	if len(results) != len(b.decoders) {
+		return fmt.Errorf("[batch] expected %d results, got %d, err %w", len(b.decoders), len(results), ErrInvalidResponse)
+	}
+
+	for _, r := range b.requests {
+		b.client.invalidateRecordURL(r.URL)
+	}
+
+	// the operations are applied, so the batch starts over empty
+	decoders := b.decoders
+	b.requests, b.decoders = nil, nil
+
+	var errs error
+	for i, r := range results {
+		if err := decoders[i](r.Status, r.Body); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("[batch] can't unmarshal result %d, err %w", i, err))
+		}
+	}
+	return errs
+}
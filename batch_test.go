@@ -0,0 +1,74 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch_NotSupported(t *testing.T) {
+	client := NewClient(defaultURL)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+
+	batch := client.NewBatch()
+	collection.InBatch(batch).Create(map[string]any{"field": "value"})
+	err := batch.Send()
+	assert.ErrorIs(t, err, ErrBatchNotSupported)
+}
+
+func TestBatch_Send(t *testing.T) {
+	type post struct {
+		ID    string `json:"id"`
+		Field string `json:"field"`
+	}
+
+	// the test server predates /api/batch, so the endpoint is emulated
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "/api/batch", r.URL.Path)
+		var body struct {
+			Requests []batchRequest `json:"requests"`
+		}
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&body)) || !assert.Len(t, body.Requests, 3) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, http.MethodPost, body.Requests[0].Method)
+		assert.Equal(t, "/api/collections/posts_public/records", body.Requests[0].URL)
+		assert.Equal(t, http.MethodPatch, body.Requests[1].Method)
+		assert.Equal(t, "/api/collections/posts_admin/records/abc", body.Requests[1].URL)
+		assert.Equal(t, http.MethodDelete, body.Requests[2].Method)
+		assert.Nil(t, body.Requests[2].Body)
+
+		_, _ = w.Write([]byte(`[
+			{"status": 200, "body": {"id": "new", "field": "created"}},
+			{"status": 200, "body": {"id": "abc", "field": "updated"}},
+			{"status": 204, "body": null}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	batch := client.NewBatch()
+	created := CollectionSet[post](client, migrations.PostsPublic).InBatch(batch).Create(post{Field: "created"})
+	updated := CollectionSet[post](client, migrations.PostsAdmin).InBatch(batch).Update("abc", map[string]any{"field": "updated"})
+	deleted := CollectionSet[post](client, migrations.PostsPublic).InBatch(batch).Delete("xyz")
+	assert.Equal(t, 3, batch.Len())
+
+	require.NoError(t, batch.Send())
+	assert.Equal(t, "new", created.Record.ID)
+	assert.Equal(t, "updated", updated.Record.Field)
+	assert.Equal(t, http.StatusNoContent, deleted.Status)
+
+	// sent operations are not executed again
+	assert.Equal(t, 0, batch.Len())
+	require.NoError(t, batch.Send())
+	assert.Equal(t, int32(1), requests.Load())
+}
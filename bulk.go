@@ -0,0 +1,123 @@
+package pocketbase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/time/rate"
+)
+
+var (
+	ErrBulkFailed  = errors.New("bulk operation failed")
+	ErrBulkSkipped = errors.New("skipped after previous error")
+)
+
+type BulkOptions struct {
+	// Concurrency is the number of requests in flight, defaults to 1.
+	Concurrency int
+	// Limiter throttles requests of the batch, nil means no limit.
+	Limiter *rate.Limiter
+	// StopOnError stops sending new requests after the first failure,
+	// unsent items are reported with ErrBulkSkipped.
+	StopOnError bool
+}
+
+type BulkResult struct {
+	Index int
+	ID    string
+	Error error
+}
+
+type BulkUpdate[T any] struct {
+	ID   string
+	Body T
+}
+
+// CreateMany creates all bodies and returns a result per body, in the same order.
+// The returned error wraps ErrBulkFailed if any item failed, or the context error if the
+// context set by WithContext was done, leaving unsent items with ErrBulkSkipped.
+func (c Collection[T]) CreateMany(bodies []T, opts BulkOptions) ([]BulkResult, error) {
+	return c.bulk("create", len(bodies), opts, func(i int) (string, error) {
+		r, err := c.Create(bodies[i])
+		return r.ID, err
+	})
+}
+
+// UpdateMany applies all updates and returns a result per update, in the same order.
+func (c Collection[T]) UpdateMany(updates []BulkUpdate[T], opts BulkOptions) ([]BulkResult, error) {
+	return c.bulk("update", len(updates), opts, func(i int) (string, error) {
+		return updates[i].ID, c.Update(updates[i].ID, updates[i].Body)
+	})
+}
+
+// DeleteMany deletes all ids and returns a result per id, in the same order.
+func (c Collection[T]) DeleteMany(ids []string, opts BulkOptions) ([]BulkResult, error) {
+	return c.bulk("delete", len(ids), opts, func(i int) (string, error) {
+		return ids[i], c.Delete(ids[i])
+	})
+}
+
+func (c Collection[T]) bulk(operation string, n int, opts BulkOptions, fn func(i int) (string, error)) ([]BulkResult, error) {
+	results := make([]BulkResult, n)
+	for i := range results {
+		results[i] = BulkResult{Index: i, Error: ErrBulkSkipped}
+	}
+	if n == 0 {
+		return results, nil
+	}
+
+	// authorize once up front, so the whole batch shares a single token refresh
+	if err := c.Authorize(); err != nil {
+		return results, err
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var g errgroup.Group
+	g.SetLimit(concurrency)
+
+	var failed atomic.Int64
+	for i := 0; i < n; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		if opts.Limiter != nil {
+			if err := opts.Limiter.Wait(ctx); err != nil {
+				break
+			}
+		}
+
+		i := i
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			id, err := fn(i)
+			results[i] = BulkResult{Index: i, ID: id, Error: err}
+			if err != nil {
+				failed.Add(1)
+				if opts.StopOnError {
+					cancel()
+				}
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	if err := c.ctx.Err(); err != nil {
+		return results, fmt.Errorf("[%s-many] stopped, err %w", operation, err)
+	}
+	if failed := failed.Load(); failed > 0 {
+		return results, fmt.Errorf("[%s-many] %d of %d operations failed, err %w", operation, failed, n, ErrBulkFailed)
+	}
+	return results, nil
+}
@@ -0,0 +1,81 @@
+package pocketbase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestCollection_Many(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := "value_" + time.Now().Format(time.StampMilli)
+	collection := Collection[map[string]any]{client, migrations.PostsPublic}
+	opts := BulkOptions{
+		Concurrency: 3,
+		Limiter:     rate.NewLimiter(rate.Limit(100), 5),
+	}
+
+	bodies := make([]map[string]any, 10)
+	for i := range bodies {
+		bodies[i] = map[string]any{"field": field}
+	}
+	created, err := collection.CreateMany(bodies, opts)
+	require.NoError(t, err)
+	require.Len(t, created, len(bodies))
+
+	updates := make([]BulkUpdate[map[string]any], len(created))
+	ids := make([]string, len(created))
+	for i, r := range created {
+		assert.Equal(t, i, r.Index)
+		assert.NoError(t, r.Error)
+		assert.NotEmpty(t, r.ID)
+		ids[i] = r.ID
+		updates[i] = BulkUpdate[map[string]any]{ID: r.ID, Body: map[string]any{"field": field + "_updated"}}
+	}
+
+	updated, err := collection.UpdateMany(updates, opts)
+	require.NoError(t, err)
+	list, err := collection.List(ParamsList{Filters: "field='" + field + "_updated'"})
+	require.NoError(t, err)
+	assert.Equal(t, len(updated), list.TotalItems)
+
+	// partial failure is reported per item
+	deleted, err := collection.DeleteMany(append(ids, "non_existing_id"), opts)
+	assert.ErrorIs(t, err, ErrBulkFailed)
+	require.Len(t, deleted, len(ids)+1)
+	for _, r := range deleted[:len(ids)] {
+		assert.NoError(t, r.Error)
+	}
+	assert.ErrorIs(t, deleted[len(ids)].Error, ErrInvalidResponse)
+}
+
+func TestCollection_ManyStopOnError(t *testing.T) {
+	client := NewClient(defaultURL)
+	collection := Collection[map[string]any]{client, migrations.PostsPublic}
+
+	results, err := collection.DeleteMany([]string{"non_existing_id", "other_id", "another_id"}, BulkOptions{
+		StopOnError: true,
+	})
+	assert.ErrorIs(t, err, ErrBulkFailed)
+	require.Len(t, results, 3)
+	assert.ErrorIs(t, results[0].Error, ErrInvalidResponse)
+	assert.ErrorIs(t, results[1].Error, ErrBulkSkipped)
+	assert.ErrorIs(t, results[2].Error, ErrBulkSkipped)
+}
+
+func TestCollection_ManyContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	collection := Collection[map[string]any]{NewClient(defaultURL).WithContext(ctx), migrations.PostsPublic}
+
+	results, err := collection.DeleteMany([]string{"non_existing_id", "other_id"}, BulkOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+	require.Len(t, results, 2)
+	assert.ErrorIs(t, results[0].Error, ErrBulkSkipped)
+	assert.ErrorIs(t, results[1].Error, ErrBulkSkipped)
+}
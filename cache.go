@@ -0,0 +1,270 @@
+package pocketbase
+
+import (
+	"container/list"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+var ErrCacheDisabled = errors.New("cache is disabled, use WithCache")
+
+// Cache stores raw responses of read operations. Keys start with the collection name,
+// so DeletePrefix(collection + "/") drops everything cached for a collection.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(key string)
+	DeletePrefix(prefix string)
+}
+
+type CacheOptions struct {
+	// Cache is the backend, NewMemoryCache(1000) by default.
+	Cache Cache
+	// TTL of cached responses, 1 minute by default.
+	TTL time.Duration
+	// Collections limits caching to these collections, all are cached if empty.
+	Collections []string
+
+	generations *cacheGenerations
+}
+
+// cacheGenerations counts invalidations per collection, so a response fetched
+// while its collection was invalidated is not cached.
+type cacheGenerations struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+func (g *cacheGenerations) get(collection string) uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.counts[collection]
+}
+
+func (g *cacheGenerations) bump(collection string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.counts[collection]++
+}
+
+// WithCache serves Client.List, Collection.List and Collection.One from a read-through cache.
+// Update, Delete, Create and batches sent by the client invalidate the affected entries;
+// use Client.WatchCache to also invalidate records changed by other clients.
+// Responses depend on the client credentials, share a Cache only between clients with the same ones.
+func WithCache(opts CacheOptions) ClientOption {
+	return func(c *Client) {
+		if opts.Cache == nil {
+			opts.Cache = NewMemoryCache(1000)
+		}
+		if opts.TTL <= 0 {
+			opts.TTL = time.Minute
+		}
+		opts.generations = &cacheGenerations{counts: map[string]uint64{}}
+		c.cache = &opts
+	}
+}
+
+func (o *CacheOptions) caches(collection string) bool {
+	if o == nil {
+		return false
+	}
+	if len(o.Collections) == 0 {
+		return true
+	}
+	for _, name := range o.Collections {
+		if name == collection {
+			return true
+		}
+	}
+	return false
+}
+
+// generation returns the invalidation count of collection, 0 when caching is disabled.
+func (o *CacheOptions) generation(collection string) uint64 {
+	if o == nil {
+		return 0
+	}
+	return o.generations.get(collection)
+}
+
+// set caches a response fetched at generation, unless the collection was invalidated since.
+// InvalidateCache bumps the generation before deleting, so it can't interleave between the check and Set.
+func (o *CacheOptions) set(collection string, generation uint64, key string, body []byte) {
+	o.generations.mu.Lock()
+	defer o.generations.mu.Unlock()
+	if o.generations.counts[collection] != generation {
+		return
+	}
+	o.Cache.Set(key, body, o.TTL)
+}
+
+func oneKey(collection, id string) string {
+	return collection + "/one/" + id
+}
+
+func listKey(collection string, params ParamsList) string {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(params.Page))
+	query.Set("perPage", strconv.Itoa(params.Size))
+	query.Set("filter", params.Filters)
+	query.Set("sort", params.Sort)
+	return collection + "/list/" + query.Encode()
+}
+
+// InvalidateCache drops the cached records with ids and all cached lists of the collection,
+// or everything cached for the collection when no id is given.
+func (c *Client) InvalidateCache(collection string, ids ...string) {
+	if c.cache == nil {
+		return
+	}
+	c.cache.generations.bump(collection)
+	if len(ids) == 0 {
+		c.cache.Cache.DeletePrefix(collection + "/")
+		return
+	}
+	for _, id := range ids {
+		c.cache.Cache.Delete(oneKey(collection, id))
+	}
+	c.cache.Cache.DeletePrefix(collection + "/list/")
+}
+
+// invalidateRecordURL invalidates the record or collection of a records API path,
+// like /api/collections/posts/records/abc.
+func (c *Client) invalidateRecordURL(path string) {
+	if c.cache == nil {
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(path, "/api/collections/"), "/")
+	if len(parts) < 2 || parts[1] != "records" {
+		return
+	}
+	if len(parts) > 2 {
+		c.InvalidateCache(parts[0], parts[2])
+		return
+	}
+	c.InvalidateCache(parts[0])
+}
+
+// WatchCache subscribes to collections and invalidates cached records changed by any client.
+// Everything cached for the collections is dropped after a reconnect, as events may have been missed.
+// It returns once the subscription is active, call stop to end it.
+func (c *Client) WatchCache(collections ...string) (stop func(), err error) {
+	if c.cache == nil {
+		return nil, ErrCacheDisabled
+	}
+	if len(collections) == 0 {
+		return nil, errors.New("[cache] at least one collection is required")
+	}
+
+	opts := SubscribeOptions{
+		ReconnectStrategy: &backoff.ZeroBackOff{},
+		OnReconnect: func() {
+			for _, collection := range collections {
+				c.InvalidateCache(collection)
+			}
+		},
+	}
+	stream, err := CollectionSet[Record](c, collections[0]).SubscribeWith(opts, collections...)
+	if err != nil {
+		return nil, err
+	}
+	invalidate := func(r Record) {
+		c.InvalidateCache(r.GetString("collectionName"), r.ID())
+	}
+	stream.OnCreate(invalidate).OnUpdate(invalidate).OnDelete(invalidate)
+	<-stream.Ready()
+	return stream.Unsubscribe, nil
+}
+
+// MemoryCache is an in-memory LRU Cache with expiring entries.
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache keeps up to size entries, evicting the least recently used ones.
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{
+		size:    size,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	el, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*memoryEntry)
+	if time.Now().After(entry.expires) {
+		m.remove(el)
+		return nil, false
+	}
+	m.lru.MoveToFront(el)
+	return entry.value, true
+}
+
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := &memoryEntry{key: key, value: value, expires: time.Now().Add(ttl)}
+	if el, ok := m.entries[key]; ok {
+		el.Value = entry
+		m.lru.MoveToFront(el)
+		return
+	}
+	m.entries[key] = m.lru.PushFront(entry)
+	for m.size > 0 && m.lru.Len() > m.size {
+		m.remove(m.lru.Back())
+	}
+}
+
+func (m *MemoryCache) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if el, ok := m.entries[key]; ok {
+		m.remove(el)
+	}
+}
+
+func (m *MemoryCache) DeletePrefix(prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, el := range m.entries {
+		if strings.HasPrefix(key, prefix) {
+			m.remove(el)
+		}
+	}
+}
+
+func (m *MemoryCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lru.Len()
+}
+
+func (m *MemoryCache) remove(el *list.Element) {
+	m.lru.Remove(el)
+	delete(m.entries, el.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,219 @@
+package pocketbase
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	cache := NewMemoryCache(2)
+	cache.Set("posts/one/a", []byte("a"), time.Minute)
+	cache.Set("posts/one/b", []byte("b"), time.Minute)
+	_, ok := cache.Get("posts/one/a")
+	assert.True(t, ok)
+
+	// b is the least recently used
+	cache.Set("posts/list/c", []byte("c"), time.Minute)
+	_, ok = cache.Get("posts/one/b")
+	assert.False(t, ok)
+	assert.Equal(t, 2, cache.Len())
+
+	cache.DeletePrefix("posts/list/")
+	_, ok = cache.Get("posts/list/c")
+	assert.False(t, ok)
+	value, ok := cache.Get("posts/one/a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("a"), value)
+
+	cache.Set("posts/one/a", []byte("a"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, ok = cache.Get("posts/one/a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestWithCache(t *testing.T) {
+	var requests atomic.Int32
+	client := NewClient(defaultURL,
+		WithCache(CacheOptions{}),
+		WithAfterSend(func(op Operation, _ *http.Request, _ *http.Response, _ error) {
+			if op.Name == OperationOne || op.Name == OperationList {
+				requests.Add(1)
+			}
+		}),
+	)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+	field := "cache_" + time.Now().Format(time.StampMilli)
+
+	created, err := collection.Create(map[string]any{"field": field})
+	require.NoError(t, err)
+	defer collection.Delete(created.ID) //nolint:errcheck
+
+	for i := 0; i < 3; i++ {
+		record, err := collection.One(created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, field, record["field"])
+	}
+	assert.Equal(t, int32(1), requests.Load())
+
+	params := ParamsList{Filters: "id='" + created.ID + "'"}
+	for i := 0; i < 3; i++ {
+		list, err := client.List(migrations.PostsPublic, params)
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+	}
+	assert.Equal(t, int32(2), requests.Load())
+
+	// local updates invalidate the record and the lists
+	require.NoError(t, collection.Update(created.ID, map[string]any{"field": field + "_updated"}))
+	record, err := collection.One(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, field+"_updated", record["field"])
+	list, err := collection.List(params)
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, field+"_updated", list.Items[0]["field"])
+	assert.Equal(t, int32(4), requests.Load())
+
+	require.NoError(t, collection.Delete(created.ID))
+	_, err = collection.One(created.ID)
+	assert.Error(t, err)
+}
+
+func TestWithCache_Invalidation(t *testing.T) {
+	var (
+		ones    atomic.Int32
+		version atomic.Int32
+		block   = make(chan struct{})
+		blocked atomic.Bool
+	)
+	// the test server predates /api/batch, so the endpoint is emulated
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/batch":
+			version.Add(1)
+			_, _ = w.Write([]byte(`[{"status": 200, "body": {"id": "abc"}}]`))
+		case "/api/collections/posts/records/abc":
+			if r.Method == http.MethodPatch {
+				version.Add(1)
+				_, _ = w.Write([]byte(`{"id": "abc"}`))
+				return
+			}
+			ones.Add(1)
+			current := version.Load()
+			if blocked.Load() {
+				// the response was read before the update, so it is stale by the time it arrives
+				<-block
+			}
+			_, _ = fmt.Fprintf(w, `{"id": "abc", "version": %d}`, current)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithCache(CacheOptions{}))
+	posts := CollectionSet[map[string]any](client, "posts")
+	one := func() float64 {
+		record, err := posts.One("abc")
+		require.NoError(t, err)
+		return record["version"].(float64)
+	}
+
+	t.Run("batch upsert", func(t *testing.T) {
+		ones.Store(0)
+		assert.Equal(t, float64(0), one())
+		assert.Equal(t, float64(0), one())
+		assert.Equal(t, int32(1), ones.Load())
+
+		batch := client.NewBatch()
+		posts.InBatch(batch).Upsert(map[string]any{"id": "abc"})
+		require.NoError(t, batch.Send())
+		assert.Equal(t, float64(1), one())
+		assert.Equal(t, int32(2), ones.Load())
+	})
+
+	t.Run("update during a fetch", func(t *testing.T) {
+		client.InvalidateCache("posts")
+		ones.Store(0)
+		blocked.Store(true)
+		stale := make(chan float64)
+		go func() {
+			record, err := posts.One("abc")
+			assert.NoError(t, err)
+			stale <- record["version"].(float64)
+		}()
+		require.Eventually(t, func() bool { return ones.Load() == 1 }, time.Second, time.Millisecond)
+		require.NoError(t, posts.Update("abc", map[string]any{"field": "value"}))
+		close(block)
+		assert.Equal(t, float64(1), <-stale)
+
+		blocked.Store(false)
+		assert.Equal(t, float64(2), one())
+		assert.Equal(t, int32(2), ones.Load())
+	})
+
+	t.Run("update during a shared fetch", func(t *testing.T) {
+		shared := CollectionSet[map[string]any](NewClient(server.URL, WithCache(CacheOptions{}), WithCoalescing()), "posts")
+		ones.Store(0)
+		block = make(chan struct{})
+		blocked.Store(true)
+		stale := make(chan float64)
+		read := func() {
+			record, err := shared.One("abc")
+			assert.NoError(t, err)
+			stale <- record["version"].(float64)
+		}
+		go read()
+		require.Eventually(t, func() bool { return ones.Load() == 1 }, time.Second, time.Millisecond)
+		require.NoError(t, shared.Update("abc", map[string]any{"field": "value"}))
+		// joins the fetch started before the update
+		go read()
+		time.Sleep(50 * time.Millisecond)
+		close(block)
+		assert.Equal(t, float64(2), <-stale)
+		assert.Equal(t, float64(2), <-stale)
+
+		blocked.Store(false)
+		record, err := shared.One("abc")
+		require.NoError(t, err)
+		assert.Equal(t, float64(3), record["version"])
+		assert.Equal(t, int32(2), ones.Load())
+	})
+}
+
+func TestClient_WatchCache(t *testing.T) {
+	_, err := NewClient(defaultURL).WatchCache(migrations.PostsPublic)
+	assert.ErrorIs(t, err, ErrCacheDisabled)
+
+	client := NewClient(defaultURL, WithCache(CacheOptions{Collections: []string{migrations.PostsPublic}}))
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+	other := CollectionSet[map[string]any](NewClient(defaultURL), migrations.PostsPublic)
+	field := "watch_" + time.Now().Format(time.StampMilli)
+
+	created, err := collection.Create(map[string]any{"field": field})
+	require.NoError(t, err)
+	defer collection.Delete(created.ID) //nolint:errcheck
+
+	stop, err := client.WatchCache(migrations.PostsPublic)
+	require.NoError(t, err)
+	defer stop()
+
+	record, err := collection.One(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, field, record["field"])
+
+	require.NoError(t, other.Update(created.ID, map[string]any{"field": field + "_remote"}))
+	assert.Eventually(t, func() bool {
+		record, err := collection.One(created.ID)
+		return err == nil && record["field"] == field+"_remote"
+	}, 5*time.Second, 50*time.Millisecond)
+}
@@ -1,13 +1,17 @@
 package pocketbase
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/duke-git/lancet/v2/convertor"
 	"github.com/go-resty/resty/v2"
+	"golang.org/x/sync/singleflight"
 )
 
 var ErrInvalidResponse = errors.New("invalid response")
@@ -17,32 +21,52 @@ type (
 		client     *resty.Client
 		url        string
 		authorizer authStore
+		decoder    Decoder
+		codec      JSONCodec
+		retry      RetryPolicy
+		limiter    *Limiter
+
+		transport        http.RoundTripper
+		transportOptions []func(*http.Transport)
+		timeout          time.Duration
+		middlewares      []Middleware
+		telemetry        *telemetry
+		logger           *slog.Logger
+		debug            bool
+		cache            *CacheOptions
+		reads            *singleflight.Group
+
+		ctx context.Context
 	}
 	ClientOption func(*Client)
 )
 
 func NewClient(url string, opts ...ClientOption) *Client {
 	client := resty.New()
-	client.
-		SetRetryCount(3).
-		SetRetryWaitTime(3 * time.Second).
-		SetRetryMaxWaitTime(10 * time.Second)
 
 	c := &Client{
 		client:     client,
 		url:        url,
 		authorizer: authorizeNoOp{},
+		codec:      stdJSONCodec{},
+		retry:      DefaultRetryPolicy(),
+		transport:  client.GetClient().Transport,
+		ctx:        context.Background(),
+		logger:     slog.Default(),
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.setupLogging()
+	client.SetTransport(c.buildTransport())
 
 	return c
 }
 
+// WithDebug logs every request and response at info level, with tokens and passwords redacted.
 func WithDebug() ClientOption {
 	return func(c *Client) {
-		c.client.SetDebug(true)
+		c.debug = true
 	}
 }
 
@@ -70,8 +94,10 @@ func WithUserToken(token string) ClientOption {
 	}
 }
 
+// Authorize refreshes the token if needed, with the context set by WithContext.
+// Concurrent refreshes share one request, which keeps the values of the first caller's context but not its cancellation.
 func (c *Client) Authorize() error {
-	return c.authorizer.authorize()
+	return c.authorizer.authorize(c.ctx)
 }
 
 func (c *Client) Update(collection string, id string, body any) error {
@@ -79,7 +105,7 @@ func (c *Client) Update(collection string, id string, body any) error {
 		return err
 	}
 
-	request := c.client.R().
+	request := c.request(Operation{Name: OperationUpdate, Collection: collection, RecordID: id}).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", collection).
 		SetBody(body)
@@ -96,6 +122,7 @@ func (c *Client) Update(collection string, id string, body any) error {
 		)
 	}
 
+	c.InvalidateCache(collection, id)
 	return nil
 }
 
@@ -106,7 +133,7 @@ func (c *Client) Create(collection string, body any) (ResponseCreate, error) {
 		return response, err
 	}
 
-	request := c.client.R().
+	request := c.request(Operation{Name: OperationCreate, Collection: collection}).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", collection).
 		SetBody(body).
@@ -118,14 +145,14 @@ func (c *Client) Create(collection string, body any) (ResponseCreate, error) {
 	}
 
 	if resp.IsError() {
-		return response, fmt.Errorf("[create] pocketbase returned status: %d, msg: %s, body: %s, err %w",
+		return response, fmt.Errorf("[create] pocketbase returned status: %d, msg: %s, err %w",
 			resp.StatusCode(),
 			resp.String(),
-			fmt.Sprintf("%+v", body), // TODO remove that after debugging
 			ErrInvalidResponse,
 		)
 	}
 
+	c.InvalidateCache(collection, resp.Result().(*ResponseCreate).ID)
 	return *resp.Result().(*ResponseCreate), nil
 }
 
@@ -134,7 +161,7 @@ func (c *Client) Delete(collection string, id string) error {
 		return err
 	}
 
-	request := c.client.R().
+	request := c.request(Operation{Name: OperationDelete, Collection: collection, RecordID: id}).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", collection).
 		SetPathParam("id", id)
@@ -152,17 +179,21 @@ func (c *Client) Delete(collection string, id string) error {
 		)
 	}
 
+	c.InvalidateCache(collection, id)
 	return nil
 }
 
-func (c *Client) List(collection string, params ParamsList) (ResponseList[map[string]any], error) {
-	var response ResponseList[map[string]any]
+func (c *Client) List(collection string, params ParamsList) (ResponseList[Record], error) {
+	return readListAs[Record](c, collection, params)
+}
 
+// list returns the undecoded response body, the caller must close it.
+func (c *Client) list(collection string, params ParamsList) (io.ReadCloser, error) {
 	if err := c.Authorize(); err != nil {
-		return response, err
+		return nil, err
 	}
 
-	request := c.client.R().
+	request := c.request(Operation{Name: OperationList, Collection: collection}).
 		SetHeader("Content-Type", "application/json").
 		SetPathParam("collection", collection)
 
@@ -179,27 +210,64 @@ func (c *Client) List(collection string, params ParamsList) (ResponseList[map[st
 		request.SetQueryParam("sort", params.Sort)
 	}
 
-	resp, err := request.Get(c.url + "/api/collections/{collection}/records")
+	resp, err := request.
+		SetDoNotParseResponse(true).
+		Get(c.url + "/api/collections/{collection}/records")
 	if err != nil {
-		return response, fmt.Errorf("[list] can't send update request to pocketbase, err %w", err)
+		return nil, fmt.Errorf("[list] can't send update request to pocketbase, err %w", err)
 	}
 
+	body := resp.RawBody()
 	if resp.IsError() {
-		return response, fmt.Errorf("[list] pocketbase returned status: %d, msg: %s, err %w",
+		defer body.Close()
+		msg, _ := io.ReadAll(body)
+		return nil, fmt.Errorf("[list] pocketbase returned status: %d, msg: %s, err %w",
 			resp.StatusCode(),
-			resp.String(),
+			msg,
 			ErrInvalidResponse,
 		)
 	}
 
-	var responseRef any = &response
-	if params.hackResponseRef != nil {
-		responseRef = params.hackResponseRef
+	return body, nil
+}
+
+func (c *Client) one(collection string, id string) ([]byte, error) {
+	if err := c.Authorize(); err != nil {
+		return nil, err
+	}
+
+	request := c.request(Operation{Name: OperationOne, Collection: collection, RecordID: id}).
+		SetHeader("Content-Type", "application/json").
+		SetPathParam("collection", collection).
+		SetPathParam("id", id)
+
+	resp, err := request.Get(c.url + "/api/collections/{collection}/records/{id}")
+	if err != nil {
+		return nil, fmt.Errorf("[one] can't send update request to pocketbase, err %w", err)
 	}
-	if err := json.Unmarshal(resp.Body(), responseRef); err != nil {
-		return response, fmt.Errorf("[list] can't unmarshal response, err %w", err)
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("[one] pocketbase returned status: %d, msg: %s, err %w",
+			resp.StatusCode(),
+			resp.String(),
+			ErrInvalidResponse,
+		)
 	}
-	return response, nil
+
+	return resp.Body(), nil
+}
+
+// WithContext returns a shallow copy of c sending its requests with ctx,
+// for cancellation and to propagate the trace of ctx. Use it with CollectionSet for typed collections.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+// request starts a request tagged with op for middlewares.
+func (c *Client) request(op Operation) *resty.Request {
+	return c.client.R().SetContext(withOperation(c.ctx, op))
 }
 
 func (c *Client) AuthStore() authStore {
@@ -1,6 +1,9 @@
 package pocketbase
 
 import (
+	"context"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -209,6 +212,71 @@ func TestAuthorizeToken(t *testing.T) {
 	}
 }
 
+func TestAuthorizeConcurrentRefresh(t *testing.T) {
+	c := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	require.NoError(t, c.Authorize())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 3; i++ {
+			expireToken(c)
+			assert.NoError(t, c.Authorize())
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			assert.True(t, c.AuthStore().IsValid())
+			return
+		default:
+			assert.NotEmpty(t, c.AuthStore().Token())
+		}
+	}
+}
+
+func TestAuthorizeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := NewClient(defaultURL, WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	_, err := c.WithContext(ctx).List(migrations.PostsAdmin, ParamsList{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoError(t, c.Authorize())
+
+	// a caller giving up doesn't fail the refresh shared with others
+	slow := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.Path, "auth-with-password") {
+			time.Sleep(100 * time.Millisecond)
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	c = NewClient(defaultURL, WithTransport(slow),
+		WithAdminEmailPassword(migrations.AdminEmailPassword, migrations.AdminEmailPassword))
+	ctx, cancel = context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		canceled <- c.WithContext(ctx).Authorize()
+	}()
+	time.Sleep(20 * time.Millisecond)
+	shared := make(chan error)
+	go func() {
+		shared <- c.Authorize()
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+	assert.NoError(t, <-shared)
+}
+
+// expireToken forces the next Authorize call to refresh the token.
+func expireToken(c *Client) {
+	a := c.authorizer.(*authorizeEmailPassword)
+	a.mu.Lock()
+	a.tokenValid = time.Time{}
+	a.mu.Unlock()
+}
+
 func TestClient_List(t *testing.T) {
 	defaultClient := NewClient(defaultURL)
 
@@ -278,6 +346,8 @@ func TestClient_Delete(t *testing.T) {
 	})
 	assert.NoError(t, err)
 	assert.NotEmpty(t, resultCreated.ID)
+	assert.False(t, resultCreated.Created.IsZero())
+	assert.WithinDuration(t, time.Now(), resultCreated.Updated.Time(), time.Minute)
 
 	// confirm item exists
 	resultList, err := client.List(migrations.PostsPublic, ParamsList{Filters: "id='" + resultCreated.ID + "'"})
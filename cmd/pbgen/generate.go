@@ -0,0 +1,251 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var initialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "id": true, "ip": true,
+	"json": true, "sql": true, "uri": true, "url": true, "uuid": true,
+}
+
+type structField struct {
+	name string
+	typ  string
+	tag  string
+}
+
+type generator struct {
+	buf         bytes.Buffer
+	collections []collection
+	byID        map[string]collection
+	usesJSON    bool
+	usesTypes   bool
+}
+
+// generate renders Go types for collections as a formatted source file of package pkg.
+func generate(pkg string, collections []collection) ([]byte, error) {
+	g := &generator{byID: map[string]collection{}}
+	for _, c := range collections {
+		g.byID[c.ID] = c
+	}
+	g.collections = append(g.collections, collections...)
+	sort.Slice(g.collections, func(i, j int) bool { return g.collections[i].Name < g.collections[j].Name })
+
+	var body bytes.Buffer
+	for _, c := range g.collections {
+		g.buf.Reset()
+		g.collection(c)
+		body.Write(g.buf.Bytes())
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by pbgen. DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkg)
+	if g.usesJSON {
+		fmt.Fprintln(&out, `"encoding/json"`)
+		fmt.Fprintln(&out)
+	}
+	fmt.Fprintf(&out, "%q\n", "github.com/r--w/pocketbase")
+	if g.usesTypes {
+		fmt.Fprintf(&out, "%q\n", "github.com/r--w/pocketbase/types")
+	}
+	fmt.Fprintln(&out, ")")
+	out.Write(body.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return out.Bytes(), fmt.Errorf("can't format generated code, err %w", err)
+	}
+	return src, nil
+}
+
+func (g *generator) collection(c collection) {
+	typeName := goName(c.Name)
+
+	g.usesTypes = true
+	fields := []structField{
+		{name: "ID", typ: "string", tag: "id,omitempty"},
+		{name: "CollectionID", typ: "string", tag: "collectionId,omitempty"},
+		{name: "CollectionName", typ: "string", tag: "collectionName,omitempty"},
+		{name: "Created", typ: "types.DateTime", tag: "created,omitempty"},
+		{name: "Updated", typ: "types.DateTime", tag: "updated,omitempty"},
+	}
+	if c.Type == "auth" {
+		fields = append(fields,
+			structField{name: "Username", typ: "string", tag: "username,omitempty"},
+			structField{name: "Email", typ: "string", tag: "email,omitempty"},
+			structField{name: "EmailVisibility", typ: "bool", tag: "emailVisibility,omitempty"},
+			structField{name: "Verified", typ: "bool", tag: "verified,omitempty"},
+		)
+	}
+
+	var expand []structField
+	for _, f := range c.Schema {
+		fields = append(fields, structField{name: goName(f.Name), typ: g.fieldType(typeName, f), tag: f.Name})
+
+		if f.Type != "relation" && f.Type != "user" {
+			continue
+		}
+		related, ok := g.byID[f.Options.CollectionID]
+		if f.Type == "user" {
+			related, ok = g.byName("users")
+		}
+		if !ok {
+			continue
+		}
+		typ := "*" + goName(related.Name)
+		if f.multiple() {
+			typ = "[]" + goName(related.Name)
+		}
+		expand = append(expand, structField{name: goName(f.Name), typ: typ, tag: f.Name + ",omitempty"})
+	}
+
+	g.printf("\n// %sCollection is the name of the %s collection.\n", typeName, c.Name)
+	g.printf("const %sCollection = %q\n", typeName, c.Name)
+
+	g.printf("\n// %s field names, for filters and sorting.\nconst (\n", typeName)
+	for _, f := range fields {
+		g.printf("%sField%s = %q\n", typeName, f.name, strings.Split(f.tag, ",")[0])
+	}
+	g.printf(")\n")
+
+	g.printf("\ntype %s struct {\n", typeName)
+	for _, f := range fields {
+		g.printf("%s %s `json:%q`\n", f.name, f.typ, f.tag)
+	}
+	if len(expand) > 0 {
+		g.printf("Expand *%sExpand `json:\"expand,omitempty\"`\n", typeName)
+	}
+	g.printf("}\n")
+
+	if len(expand) > 0 {
+		g.printf("\n// %sExpand holds relations loaded with the expand parameter.\n", typeName)
+		g.printf("type %sExpand struct {\n", typeName)
+		for _, f := range expand {
+			g.printf("%s %s `json:%q`\n", f.name, f.typ, f.tag)
+		}
+		g.printf("}\n")
+	}
+
+	for _, f := range c.Schema {
+		if f.Type == "select" && len(f.Options.Values) > 0 {
+			g.enum(typeName+goName(f.Name), f.Options.Values)
+		}
+	}
+
+	g.printf("\nfunc New%sCollection(client *pocketbase.Client) pocketbase.Collection[%s] {\n", typeName, typeName)
+	g.printf("return pocketbase.CollectionSet[%s](client, %sCollection)\n}\n", typeName, typeName)
+}
+
+func (g *generator) enum(typeName string, values []string) {
+	g.printf("\ntype %s string\n\nconst (\n", typeName)
+	seen := map[string]int{}
+	for _, v := range values {
+		name := typeName + goName(v)
+		if name == typeName {
+			name += "Value"
+		}
+		if seen[name]++; seen[name] > 1 {
+			name += strconv.Itoa(seen[name])
+		}
+		g.printf("%s %s = %q\n", name, typeName, v)
+	}
+	g.printf(")\n")
+}
+
+func (g *generator) fieldType(typeName string, f field) string {
+	var typ string
+	switch f.Type {
+	case "number":
+		typ = "float64"
+	case "bool":
+		typ = "bool"
+	case "json":
+		g.usesJSON = true
+		return "json.RawMessage"
+	case "date":
+		return "types.DateTime"
+	case "geoPoint":
+		return "types.GeoPoint"
+	case "file":
+		if f.multiple() {
+			return "types.Files"
+		}
+		typ = "string"
+	case "relation", "user", "select":
+		// multiple values are types.Strings, enum constants still apply to their elements
+		if f.multiple() {
+			return "types.Strings"
+		}
+		typ = "string"
+		if f.Type == "select" && len(f.Options.Values) > 0 {
+			typ = typeName + goName(f.Name)
+		}
+	default:
+		// text, email, url and editor
+		typ = "string"
+	}
+	return typ
+}
+
+func (g *generator) byName(name string) (collection, bool) {
+	for _, c := range g.collections {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return collection{}, false
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// goName converts snake_case, kebab-case and camelCase names to exported Go identifiers.
+func goName(s string) string {
+	var (
+		words []string
+		word  []rune
+	)
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	prev := rune(0)
+	for _, r := range s {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	flush()
+
+	var name strings.Builder
+	for _, w := range words {
+		if initialisms[strings.ToLower(w)] {
+			name.WriteString(strings.ToUpper(w))
+			continue
+		}
+		runes := []rune(w)
+		name.WriteRune(unicode.ToUpper(runes[0]))
+		name.WriteString(string(runes[1:]))
+	}
+	if name.Len() > 0 && unicode.IsDigit([]rune(name.String())[0]) {
+		return "V" + name.String()
+	}
+	return name.String()
+}
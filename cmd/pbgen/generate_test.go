@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"posts_public":    "PostsPublic",
+		"emailVisibility": "EmailVisibility",
+		"collectionId":    "CollectionID",
+		"external-url":    "ExternalURL",
+		"2fa":             "V2fa",
+		"in progress":     "InProgress",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, goName(in), in)
+	}
+}
+
+func TestGenerate_Snapshot(t *testing.T) {
+	collections, err := loadSnapshot("../../migrations/1668292995_collections_snapshot.go")
+	require.NoError(t, err)
+	require.Len(t, collections, 4)
+
+	src, err := generate("models", collections)
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "collections.go", src, 0)
+	require.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, "type PostsPublic struct")
+	assert.Contains(t, code, `PostsPublicFieldField          = "field"`)
+	assert.Contains(t, code, "func NewPostsPublicCollection(client *pocketbase.Client) pocketbase.Collection[PostsPublic]")
+	assert.Contains(t, code, "Verified        bool           `json:\"verified,omitempty\"`")
+	assert.Contains(t, code, "Avatar          string         `json:\"avatar\"`")
+}
+
+func TestGenerate_RelationsAndSelects(t *testing.T) {
+	one, many := 1, 5
+	collections := []collection{
+		{ID: "tags_id", Name: "tags", Type: "base", Schema: []field{
+			{Name: "name", Type: "text"},
+		}},
+		{ID: "posts_id", Name: "posts", Type: "base", Schema: []field{
+			{Name: "status", Type: "select", Options: fieldOptions{MaxSelect: &one, Values: []string{"draft", "in review"}}},
+			{Name: "labels", Type: "select", Options: fieldOptions{MaxSelect: &many, Values: []string{"new", "hot"}}},
+			{Name: "tags", Type: "relation", Options: fieldOptions{MaxSelect: &many, CollectionID: "tags_id"}},
+			{Name: "main_tag", Type: "relation", Options: fieldOptions{MaxSelect: &one, CollectionID: "tags_id"}},
+			{Name: "meta", Type: "json"},
+			{Name: "location", Type: "geoPoint"},
+		}},
+	}
+
+	src, err := generate("models", collections)
+	require.NoError(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "collections.go", src, 0)
+	require.NoError(t, err)
+
+	code := string(src)
+	assert.Contains(t, code, `"encoding/json"`)
+	assert.Contains(t, code, "Status         PostsStatus")
+	assert.Contains(t, code, `PostsStatusInReview PostsStatus = "in review"`)
+	assert.Contains(t, code, "Tags           types.Strings")
+	assert.Contains(t, code, "Labels         types.Strings")
+	assert.Contains(t, code, `PostsLabelsHot PostsLabels = "hot"`)
+	assert.Contains(t, code, "Created        types.DateTime")
+	assert.Contains(t, code, "Location       types.GeoPoint")
+	assert.Contains(t, code, "Expand         *PostsExpand")
+	assert.Contains(t, code, "Tags    []Tags `json:\"tags,omitempty\"`")
+	assert.Contains(t, code, "MainTag *Tags  `json:\"main_tag,omitempty\"`")
+}
+
+func TestLoadServer_Timeout(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-unblock:
+		}
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	start := time.Now()
+	_, err := loadServer(server.URL, "admin@admin.com", "secret", 100*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,82 @@
+// Command pbgen generates Go structs, field-name constants, select enums and
+// typed collection constructors from a PocketBase schema.
+//
+//	pbgen -snapshot migrations/1668292995_collections_snapshot.go -package models -out models/collections.go
+//	pbgen -url http://127.0.0.1:8090 -email admin@admin.com -password secret -out models/collections.go
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+func main() {
+	var (
+		snapshot = flag.String("snapshot", "", "migration file with the collections snapshot")
+		url      = flag.String("url", "", "PocketBase url to read collections from /api/collections")
+		email    = flag.String("email", "", "admin email, required with -url")
+		password = flag.String("password", "", "admin password, required with -url")
+		timeout  = flag.Duration("timeout", 30*time.Second, "time limit for reading collections with -url")
+		pkg      = flag.String("package", "models", "package name of the generated file")
+		out      = flag.String("out", "", "output file, stdout if empty")
+		names    = flag.String("collections", "", "comma separated collection names to generate, all if empty")
+		system   = flag.Bool("system", false, "include system collections")
+	)
+	flag.Parse()
+
+	var (
+		collections []collection
+		err         error
+	)
+	switch {
+	case *snapshot != "":
+		collections, err = loadSnapshot(*snapshot)
+	case *url != "":
+		collections, err = loadServer(*url, *email, *password, *timeout)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(*pkg, filter(collections, *names, *system))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*out, src, 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// filter keeps the requested collections, relations to skipped collections get no expand type.
+func filter(collections []collection, names string, system bool) []collection {
+	wanted := map[string]bool{}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			wanted[name] = true
+		}
+	}
+
+	var result []collection
+	for _, c := range collections {
+		if c.System && !system {
+			continue
+		}
+		if len(wanted) > 0 && !wanted[c.Name] {
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/r--w/pocketbase"
+)
+
+type collection struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Type   string  `json:"type"`
+	System bool    `json:"system"`
+	Schema []field `json:"schema"`
+}
+
+type field struct {
+	Name     string       `json:"name"`
+	Type     string       `json:"type"`
+	Required bool         `json:"required"`
+	Options  fieldOptions `json:"options"`
+}
+
+type fieldOptions struct {
+	MaxSelect    *int     `json:"maxSelect"`
+	Values       []string `json:"values"`
+	CollectionID string   `json:"collectionId"`
+}
+
+// multiple reports whether the field holds a list of values.
+func (f field) multiple() bool {
+	switch f.Type {
+	case "select", "file":
+		return f.Options.MaxSelect != nil && *f.Options.MaxSelect > 1
+	case "relation", "user":
+		// nil maxSelect means no limit for relations
+		return f.Options.MaxSelect == nil || *f.Options.MaxSelect > 1
+	}
+	return false
+}
+
+// loadSnapshot reads collections from a migration file embedding them as a JSON string,
+// like the ones created by PocketBase's "migrate collections" command.
+func loadSnapshot(path string) ([]collection, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse snapshot %s, err %w", path, err)
+	}
+
+	var (
+		collections []collection
+		found       bool
+	)
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.BasicLit)
+		if found || !ok || lit.Kind != token.STRING {
+			return !found
+		}
+		value, err := strconv.Unquote(lit.Value)
+		if err != nil || !strings.HasPrefix(strings.TrimSpace(value), "[") {
+			return true
+		}
+		if err := json.Unmarshal([]byte(value), &collections); err == nil {
+			found = true
+		}
+		return !found
+	})
+	if !found {
+		return nil, errors.New("no collections JSON found in snapshot " + path)
+	}
+	return collections, nil
+}
+
+// loadServer reads collections from /api/collections, which requires admin credentials.
+// timeout bounds the whole load, so an unresponsive server can't block the generator.
+func loadServer(url, email, password string, timeout time.Duration) ([]collection, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// WithTimeout also bounds the token request, which is shared and so isn't canceled with ctx
+	client := pocketbase.NewClient(url, pocketbase.WithAdminEmailPassword(email, password),
+		pocketbase.WithTimeout(timeout)).WithContext(ctx)
+	if err := client.Authorize(); err != nil {
+		return nil, err
+	}
+
+	var collections []collection
+	for page := 1; ; page++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/api/collections?page=%d&perPage=200", url, page), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", client.AuthStore().Token())
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("can't list collections, err %w", err)
+		}
+		var list pocketbase.ResponseList[collection]
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("can't list collections, status: %d", resp.StatusCode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't decode collections, err %w", err)
+		}
+
+		collections = append(collections, list.Items...)
+		if page >= list.TotalPages {
+			return collections, nil
+		}
+	}
+}
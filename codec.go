@@ -0,0 +1,48 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONCodec encodes request bodies and decodes responses and realtime events.
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONStreamCodec is a JSONCodec that can decode straight from a response body,
+// list responses are then decoded without being buffered first.
+type JSONStreamCodec interface {
+	JSONCodec
+	NewDecoder(r io.Reader) JSONStreamDecoder
+}
+
+type JSONStreamDecoder interface {
+	Decode(v any) error
+}
+
+// stdJSONCodec is the default codec backed by encoding/json.
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (stdJSONCodec) NewDecoder(r io.Reader) JSONStreamDecoder {
+	return json.NewDecoder(r)
+}
+
+// WithJSONCodec replaces encoding/json for request bodies, responses and realtime events,
+// e.g. with goccy/go-json or jsoniter. Implement JSONStreamCodec to stream list responses.
+func WithJSONCodec(codec JSONCodec) ClientOption {
+	return func(c *Client) {
+		c.codec = codec
+		c.client.JSONMarshal = codec.Marshal
+		c.client.JSONUnmarshal = codec.Unmarshal
+	}
+}
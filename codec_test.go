@@ -0,0 +1,144 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingCodec struct {
+	marshal, unmarshal, stream atomic.Int32
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshal.Add(1)
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshal.Add(1)
+	return json.Unmarshal(data, v)
+}
+
+type countingStreamCodec struct {
+	*countingCodec
+}
+
+func (c countingStreamCodec) NewDecoder(r io.Reader) JSONStreamDecoder {
+	c.stream.Add(1)
+	return json.NewDecoder(r)
+}
+
+func TestWithJSONCodec(t *testing.T) {
+	tests := []struct {
+		name       string
+		codec      func(*countingCodec) JSONCodec
+		wantStream bool
+	}{
+		{
+			name:  "Codec",
+			codec: func(c *countingCodec) JSONCodec { return c },
+		},
+		{
+			name:       "Stream codec",
+			codec:      func(c *countingCodec) JSONCodec { return countingStreamCodec{c} },
+			wantStream: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := &countingCodec{}
+			client := NewClient(defaultURL, WithJSONCodec(tt.codec(counter)))
+			posts := CollectionSet[map[string]any](client, migrations.PostsPublic)
+			field := "codec_" + time.Now().Format(time.StampMilli)
+
+			created, err := posts.Create(map[string]any{"field": field})
+			require.NoError(t, err)
+			assert.Equal(t, int32(1), counter.marshal.Load())
+			assert.Equal(t, int32(1), counter.unmarshal.Load())
+
+			list, err := posts.List(ParamsList{Filters: "id='" + created.ID + "'"})
+			require.NoError(t, err)
+			require.Len(t, list.Items, 1)
+			assert.Equal(t, field, list.Items[0]["field"])
+			if tt.wantStream {
+				assert.Equal(t, int32(1), counter.stream.Load())
+				assert.Equal(t, int32(1), counter.unmarshal.Load())
+			} else {
+				assert.Equal(t, int32(2), counter.unmarshal.Load())
+			}
+
+			record, err := posts.One(created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, field, record["field"])
+
+			assert.NoError(t, posts.Delete(created.ID))
+		})
+	}
+}
+
+func TestClient_ListError(t *testing.T) {
+	_, err := NewClient(defaultURL).List("invalid_collection", ParamsList{})
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "wasn't found")
+}
+
+func TestWithJSONCodec_Records(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		decoded []string
+	)
+	decoder := DecoderFunc(func(data []byte, v any) error {
+		mu.Lock()
+		decoded = append(decoded, string(data))
+		mu.Unlock()
+		return json.Unmarshal(data, v)
+	})
+	codec := &countingCodec{}
+	client := NewClient(defaultURL, WithJSONCodec(codec), WithDecoder(decoder))
+	posts := CollectionSet[map[string]any](client, migrations.PostsPublic)
+	field := "codec_" + time.Now().Format(time.StampMilli)
+
+	stream, err := posts.Subscribe()
+	require.NoError(t, err)
+	defer stream.Unsubscribe()
+	<-stream.Ready()
+
+	created, err := posts.Create(map[string]any{"field": field})
+	require.NoError(t, err)
+	defer posts.Delete(created.ID)
+
+	select {
+	case e := <-stream.Events():
+		require.NoError(t, e.Error)
+		assert.Equal(t, created.ID, e.Record["id"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no create event")
+	}
+
+	record, err := posts.NewLoader(LoaderOptions{}).Load(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, field, record["field"])
+
+	marshaled := codec.marshal.Load()
+	require.NoError(t, posts.PatchDiff(created.ID, map[string]any{"field": field}, map[string]any{"field": field + "_patched"}))
+	assert.Greater(t, codec.marshal.Load(), marshaled+1)
+
+	// the decoder sees single records, never protocol payloads
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, decoded)
+	for _, data := range decoded {
+		assert.NotContains(t, data, "clientId")
+		assert.NotContains(t, data, `"action"`)
+		assert.NotContains(t, data, `"items"`)
+	}
+}
@@ -1,9 +1,6 @@
 package pocketbase
 
-import (
-	"encoding/json"
-	"fmt"
-)
+import "fmt"
 
 type Collection[T any] struct {
 	*Client
@@ -27,39 +24,18 @@ func (c Collection[T]) Delete(id string) error {
 }
 
 func (c Collection[T]) List(params ParamsList) (ResponseList[T], error) {
-	var response ResponseList[T]
-	params.hackResponseRef = &response
-
-	_, err := c.Client.List(c.Name, params)
-	return response, err
+	return readListAs[T](c.Client, c.Name, params)
 }
 
 func (c Collection[T]) One(id string) (T, error) {
 	var response T
 
-	if err := c.Authorize(); err != nil {
-		return response, err
-	}
-
-	request := c.client.R().
-		SetHeader("Content-Type", "application/json").
-		SetPathParam("collection", c.Name).
-		SetPathParam("id", id)
-
-	resp, err := request.Get(c.url + "/api/collections/{collection}/records/{id}")
+	body, err := c.Client.readOne(c.Name, id)
 	if err != nil {
-		return response, fmt.Errorf("[one] can't send update request to pocketbase, err %w", err)
-	}
-
-	if resp.IsError() {
-		return response, fmt.Errorf("[one] pocketbase returned status: %d, msg: %s, err %w",
-			resp.StatusCode(),
-			resp.String(),
-			ErrInvalidResponse,
-		)
+		return response, err
 	}
 
-	if err := json.Unmarshal(resp.Body(), &response); err != nil {
+	if err := c.decode(body, &response); err != nil {
 		return response, fmt.Errorf("[one] can't unmarshal response, err %w", err)
 	}
 	return response, nil
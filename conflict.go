@@ -0,0 +1,89 @@
+package pocketbase
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/duke-git/lancet/v2/convertor"
+)
+
+var ErrConflict = errors.New("record was modified concurrently")
+
+// ConflictError reports a failed optimistic concurrency check, errors.Is(err, ErrConflict) matches it.
+type ConflictError struct {
+	ID       string
+	Field    string
+	Expected string
+	Actual   string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("record %s %s is %s, expected %s: %s", e.ID, e.Field, e.Actual, e.Expected, ErrConflict)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// UpdateIfUnchanged patches the record only if its updated timestamp still equals expectedUpdated.
+// PocketBase has no conditional update, so the record is re-read right before patching;
+// a write landing between the read and the patch is not detected.
+func (c *Client) UpdateIfUnchanged(collection string, id string, expectedUpdated string, body any) error {
+	return c.UpdateIfField(collection, id, "updated", expectedUpdated, body)
+}
+
+// UpdateIfField is UpdateIfUnchanged for a user-defined version field.
+func (c *Client) UpdateIfField(collection string, id string, field string, expected any, body any) error {
+	raw, err := c.one(collection, id)
+	if err != nil {
+		return err
+	}
+
+	var current Record
+	if err := c.decode(raw, &current); err != nil {
+		return fmt.Errorf("[update-if] can't unmarshal response, err %w", err)
+	}
+	if actual, expected := convertor.ToString(current[field]), convertor.ToString(expected); actual != expected {
+		return &ConflictError{ID: id, Field: field, Expected: expected, Actual: actual}
+	}
+
+	return c.Update(collection, id, body)
+}
+
+func (c Collection[T]) UpdateIfUnchanged(id string, expectedUpdated string, body T) error {
+	return c.Client.UpdateIfUnchanged(c.Name, id, expectedUpdated, body)
+}
+
+func (c Collection[T]) UpdateIfField(id string, field string, expected any, body T) error {
+	return c.Client.UpdateIfField(c.Name, id, field, expected, body)
+}
+
+// UpdateFunc runs a read-modify-write cycle: it reads the record, passes it to fn and stores the result
+// with UpdateIfUnchanged, starting over on conflict up to attempts times.
+func (c Collection[T]) UpdateFunc(id string, attempts int, fn func(current T) (T, error)) error {
+	for attempt := 1; ; attempt++ {
+		raw, err := c.Client.one(c.Name, id)
+		if err != nil {
+			return err
+		}
+
+		var current T
+		if err := c.decode(raw, &current); err != nil {
+			return fmt.Errorf("[update-func] can't unmarshal response, err %w", err)
+		}
+		var meta Record
+		if err := c.decode(raw, &meta); err != nil {
+			return fmt.Errorf("[update-func] can't unmarshal response, err %w", err)
+		}
+
+		next, err := fn(current)
+		if err != nil {
+			return err
+		}
+
+		err = c.UpdateIfUnchanged(id, meta.GetString("updated"), next)
+		if !errors.Is(err, ErrConflict) || attempt >= attempts {
+			return err
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package pocketbase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollection_UpdateIfUnchanged(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := "value_" + time.Now().Format(time.StampMilli)
+	collection := Collection[map[string]any]{client, migrations.PostsPublic}
+
+	created, err := collection.Create(map[string]any{"field": field})
+	require.NoError(t, err)
+	item, err := collection.One(created.ID)
+	require.NoError(t, err)
+	stale := item["updated"].(string)
+
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, collection.UpdateIfUnchanged(created.ID, stale, map[string]any{"field": field + "_1"}))
+
+	// second writer with the stale timestamp loses
+	err = collection.UpdateIfUnchanged(created.ID, stale, map[string]any{"field": field + "_2"})
+	assert.ErrorIs(t, err, ErrConflict)
+	var conflict *ConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, stale, conflict.Expected)
+	assert.NotEqual(t, stale, conflict.Actual)
+
+	// user-defined version field
+	assert.NoError(t, collection.UpdateIfField(created.ID, "field", field+"_1", map[string]any{"field": field + "_3"}))
+	assert.ErrorIs(t, collection.UpdateIfField(created.ID, "field", field+"_1", map[string]any{"field": field + "_4"}), ErrConflict)
+}
+
+func TestCollection_UpdateFunc(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := "value_" + time.Now().Format(time.StampMilli)
+	collection := Collection[map[string]any]{client, migrations.PostsPublic}
+
+	created, err := collection.Create(map[string]any{"field": field})
+	require.NoError(t, err)
+
+	calls := 0
+	err = collection.UpdateFunc(created.ID, 3, func(current map[string]any) (map[string]any, error) {
+		calls++
+		if calls == 1 {
+			// concurrent writer between read and write
+			time.Sleep(10 * time.Millisecond)
+			require.NoError(t, collection.Update(created.ID, map[string]any{"field": field + "_concurrent"}))
+		}
+		return map[string]any{"field": current["field"].(string) + "_func"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	item, err := collection.One(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, field+"_concurrent_func", item["field"])
+}
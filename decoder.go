@@ -0,0 +1,134 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Decoder decodes response bodies into records, e.g. to post-process records.
+// It is called once per record, list responses are split into records with the client JSON codec,
+// which also decodes records by default.
+type Decoder interface {
+	Decode(data []byte, v any) error
+}
+
+// DecoderFunc adapts a function to Decoder.
+type DecoderFunc func(data []byte, v any) error
+
+func (f DecoderFunc) Decode(data []byte, v any) error {
+	return f(data, v)
+}
+
+func WithDecoder(decoder Decoder) ClientOption {
+	return func(c *Client) {
+		c.decoder = decoder
+	}
+}
+
+func (c *Client) decode(data []byte, v any) error {
+	if c.decoder != nil {
+		return c.decoder.Decode(data, v)
+	}
+	return c.codec.Unmarshal(data, v)
+}
+
+// unmarshalFrom decodes r with the codec only, for protocol payloads that a Decoder must not see.
+func (c *Client) unmarshalFrom(r io.Reader, v any) error {
+	if stream, ok := c.codec.(JSONStreamCodec); ok {
+		return stream.NewDecoder(r).Decode(v)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return c.codec.Unmarshal(data, v)
+}
+
+// listAs lists records of the collection decoded into T, bypassing the cache.
+func listAs[T any](c *Client, collection string, params ParamsList) (ResponseList[T], error) {
+	return decodeList[T](c, c.list, collection, params)
+}
+
+// readListAs is listAs served from the cache and coalesced when enabled.
+func readListAs[T any](c *Client, collection string, params ParamsList) (ResponseList[T], error) {
+	return decodeList[T](c, c.readList, collection, params)
+}
+
+// decodeList decodes a list response, with the Decoder called once per record when one is set.
+func decodeList[T any](c *Client, fetch func(string, ParamsList) (io.ReadCloser, error), collection string, params ParamsList) (ResponseList[T], error) {
+	var response ResponseList[T]
+
+	body, err := fetch(collection, params)
+	if err != nil {
+		return response, err
+	}
+	defer body.Close()
+
+	if c.decoder == nil {
+		if err := c.unmarshalFrom(body, &response); err != nil {
+			return response, fmt.Errorf("[list] can't unmarshal response, err %w", err)
+		}
+		return response, nil
+	}
+	return decodeItems(c, body, func(data []byte) (T, error) {
+		var record T
+		return record, c.decode(data, &record)
+	})
+}
+
+// decodeItems reads the list envelope with the codec and decodes every record with decode.
+func decodeItems[T any](c *Client, body io.Reader, decode func(data []byte) (T, error)) (ResponseList[T], error) {
+	var raw ResponseList[json.RawMessage]
+	if err := c.unmarshalFrom(body, &raw); err != nil {
+		return ResponseList[T]{}, fmt.Errorf("[list] can't unmarshal response, err %w", err)
+	}
+	response := ResponseList[T]{
+		Page:       raw.Page,
+		PerPage:    raw.PerPage,
+		TotalItems: raw.TotalItems,
+		TotalPages: raw.TotalPages,
+		Items:      make([]T, 0, len(raw.Items)),
+	}
+	for _, item := range raw.Items {
+		record, err := decode(item)
+		if err != nil {
+			return response, fmt.Errorf("[list] can't unmarshal record, err %w", err)
+		}
+		response.Items = append(response.Items, record)
+	}
+	return response, nil
+}
+
+// idRecord is a record decoded into T along with its id, which T may not expose.
+type idRecord[T any] struct {
+	ID     string
+	Record T
+}
+
+// decodeIDRecord reads the id with the codec and decodes the record with the client Decoder.
+func decodeIDRecord[T any](c *Client, data []byte) (idRecord[T], error) {
+	var r idRecord[T]
+	var meta struct {
+		ID string `json:"id"`
+	}
+	if err := c.codec.Unmarshal(data, &meta); err != nil {
+		return r, err
+	}
+	r.ID = meta.ID
+	return r, c.decode(data, &r.Record)
+}
+
+// listWithIDs lists records of the collection keeping their ids, bypassing the cache.
+// It is a function, as a Collection[idRecord[T]] would make Collection[T] methods instantiate themselves recursively.
+func listWithIDs[T any](c *Client, collection string, params ParamsList) (ResponseList[idRecord[T]], error) {
+	body, err := c.list(collection, params)
+	if err != nil {
+		return ResponseList[idRecord[T]]{}, err
+	}
+	defer body.Close()
+
+	return decodeItems(c, body, func(data []byte) (idRecord[T], error) {
+		return decodeIDRecord[T](c, data)
+	})
+}
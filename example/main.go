@@ -5,7 +5,6 @@ import (
 	"log"
 	"time"
 
-	"github.com/mitchellh/mapstructure"
 	"github.com/r--w/pocketbase"
 )
 
@@ -25,6 +24,7 @@ func main() {
 	// pocketbase.WithUserToken(token)
 	// pocketbase.WithAdminToken(token)
 	// pocketbase.WithDebug()
+	// pocketbase.WithRetryPolicy(pocketbase.RetryPolicy{MaxRetries: 5})
 
 	response, err := client.List("posts_public", pocketbase.ParamsList{
 		Size:    1,
@@ -37,11 +37,14 @@ func main() {
 
 	log.Printf("Total items: %d, total pages: %d\n", response.TotalItems, response.TotalPages)
 	for _, item := range response.Items {
-		var test Post
-		err := mapstructure.Decode(item, &test)
-		errs = errors.Join(errs, err)
+		log.Printf("Item: %s, field: %s, created: %s\n", item.ID(), item.GetString("field"), item.GetTime("created"))
+	}
 
-		log.Printf("Item: %#v\n", test)
+	// or decode records straight into your struct with a typed collection
+	posts, err := pocketbase.CollectionSet[Post](client, "posts_public").List(pocketbase.ParamsList{Size: 1, Page: 1})
+	errs = errors.Join(errs, err)
+	for _, post := range posts.Items {
+		log.Printf("Post: %#v\n", post)
 	}
 
 	log.Println("Inserting new item")
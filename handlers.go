@@ -0,0 +1,163 @@
+package pocketbase
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+)
+
+type streamHandlers[T any] struct {
+	workers int
+	logger  *slog.Logger
+
+	mu       sync.RWMutex
+	onCreate []func(T)
+	onUpdate []func(T)
+	onDelete []func(T)
+	onError  []func(error)
+
+	start sync.Once
+}
+
+// OnCreate registers fn for create events. Handlers start consuming the stream on the first registration.
+func (s *Stream[T]) OnCreate(fn func(record T)) *Stream[T] {
+	s.handlers.mu.Lock()
+	s.handlers.onCreate = append(s.handlers.onCreate, fn)
+	s.handlers.mu.Unlock()
+	s.startHandlers()
+	return s
+}
+
+// OnUpdate registers fn for update events.
+func (s *Stream[T]) OnUpdate(fn func(record T)) *Stream[T] {
+	s.handlers.mu.Lock()
+	s.handlers.onUpdate = append(s.handlers.onUpdate, fn)
+	s.handlers.mu.Unlock()
+	s.startHandlers()
+	return s
+}
+
+// OnDelete registers fn for delete events, record is the last state of the deleted record.
+func (s *Stream[T]) OnDelete(fn func(record T)) *Stream[T] {
+	s.handlers.mu.Lock()
+	s.handlers.onDelete = append(s.handlers.onDelete, fn)
+	s.handlers.mu.Unlock()
+	s.startHandlers()
+	return s
+}
+
+// OnError registers fn for event decode failures and panics recovered from other handlers.
+func (s *Stream[T]) OnError(fn func(err error)) *Stream[T] {
+	s.handlers.mu.Lock()
+	s.handlers.onError = append(s.handlers.onError, fn)
+	s.handlers.mu.Unlock()
+	s.startHandlers()
+	return s
+}
+
+func (s *Stream[T]) startHandlers() {
+	s.handlers.start.Do(func() {
+		events := s.Events()
+		errs := s.Errors()
+
+		workers := s.handlers.workers
+		if workers < 1 {
+			workers = 1
+		}
+		queues := make([]chan Event[T], workers)
+		for i := range queues {
+			queues[i] = make(chan Event[T])
+			go func(queue <-chan Event[T]) {
+				for e := range queue {
+					s.handlers.handleEvent(e)
+				}
+			}(queues[i])
+		}
+
+		go func() {
+			defer func() {
+				for _, queue := range queues {
+					close(queue)
+				}
+			}()
+			for events != nil || errs != nil {
+				select {
+				case e, ok := <-events:
+					if !ok {
+						events = nil
+						continue
+					}
+					queues[s.handlers.shard(e, workers)] <- e
+				case err, ok := <-errs:
+					if !ok {
+						errs = nil
+						continue
+					}
+					s.handlers.handleError(err)
+				}
+			}
+		}()
+	})
+}
+
+// shard keeps all events of a record on the same worker.
+func (h *streamHandlers[T]) shard(e Event[T], workers int) int {
+	if workers == 1 {
+		return 0
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(e.id))
+	return int(hash.Sum32() % uint32(workers))
+}
+
+func (h *streamHandlers[T]) handleEvent(e Event[T]) {
+	if e.Error != nil {
+		h.handleError(e.Error)
+		return
+	}
+
+	h.mu.RLock()
+	var fns []func(T)
+	switch e.Action {
+	case ActionCreate:
+		fns = h.onCreate
+	case ActionUpdate:
+		fns = h.onUpdate
+	case ActionDelete:
+		fns = h.onDelete
+	}
+	h.mu.RUnlock()
+
+	for _, fn := range fns {
+		if err := h.call(func() { fn(e.Record) }); err != nil {
+			h.handleError(fmt.Errorf("[realtime] %s handler panicked, err %w", e.Action, err))
+		}
+	}
+}
+
+func (h *streamHandlers[T]) handleError(err error) {
+	h.mu.RLock()
+	fns := h.onError
+	h.mu.RUnlock()
+
+	if len(fns) == 0 {
+		h.logger.Error("realtime handler failed", "err", err)
+		return
+	}
+	for _, fn := range fns {
+		if panicErr := h.call(func() { fn(err) }); panicErr != nil {
+			h.logger.Error("realtime error handler panicked", "err", panicErr)
+		}
+	}
+}
+
+func (h *streamHandlers[T]) call(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	fn()
+	return nil
+}
@@ -0,0 +1,90 @@
+package pocketbase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream_Handlers(t *testing.T) {
+	client := NewClient(defaultURL)
+	defaultBody := map[string]interface{}{
+		"field": "value_" + time.Now().Format(time.StampMilli),
+	}
+	collection := Collection[map[string]any]{client, migrations.PostsPublic}
+	stream, err := collection.SubscribeWith(SubscribeOptions{
+		ReconnectStrategy: &backoff.ZeroBackOff{},
+		HandlerWorkers:    4,
+	})
+	require.NoError(t, err)
+	defer stream.Unsubscribe()
+	<-stream.Ready()
+
+	created := make(chan string, 1)
+	updated := make(chan string, 1)
+	deleted := make(chan string, 1)
+	stream.
+		OnCreate(func(record map[string]any) { created <- record["id"].(string) }).
+		OnUpdate(func(record map[string]any) { updated <- record["id"].(string) }).
+		OnDelete(func(record map[string]any) { deleted <- record["id"].(string) })
+
+	wait := func(ch <-chan string) string {
+		select {
+		case id := <-ch:
+			return id
+		case <-time.After(5 * time.Second):
+			t.Fatal("handler was not called")
+		}
+		return ""
+	}
+
+	resp, err := collection.Create(defaultBody)
+	require.NoError(t, err)
+	assert.Equal(t, resp.ID, wait(created))
+
+	require.NoError(t, collection.Update(resp.ID, defaultBody))
+	assert.Equal(t, resp.ID, wait(updated))
+
+	require.NoError(t, collection.Delete(resp.ID))
+	assert.Equal(t, resp.ID, wait(deleted))
+}
+
+func TestStream_HandlersPanicRecovery(t *testing.T) {
+	client := NewClient(defaultURL)
+	defaultBody := map[string]interface{}{
+		"field": "value_" + time.Now().Format(time.StampMilli),
+	}
+	collection := Collection[map[string]any]{client, migrations.PostsPublic}
+	stream, err := collection.Subscribe()
+	require.NoError(t, err)
+	defer stream.Unsubscribe()
+	<-stream.Ready()
+
+	errs := make(chan error, 2)
+	created := make(chan struct{}, 2)
+	stream.
+		OnCreate(func(record map[string]any) { panic("handler failure") }).
+		OnCreate(func(record map[string]any) { created <- struct{}{} }).
+		OnError(func(err error) { errs <- err })
+
+	for i := 0; i < 2; i++ {
+		_, err := collection.Create(defaultBody)
+		require.NoError(t, err)
+
+		select {
+		case err := <-errs:
+			assert.ErrorContains(t, err, "handler failure")
+		case <-time.After(5 * time.Second):
+			t.Fatal("panic was not reported")
+		}
+		select {
+		case <-created:
+		case <-time.After(5 * time.Second):
+			t.Fatal("second handler was not called")
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package pocketbase
+
+import (
+	"context"
+	"net/http"
+	"path"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter throttles requests with a token bucket and caps the number of requests in flight,
+// e.g. to stay under PocketBase's rate limiter. A request is in flight until its response headers arrive.
+// One Limiter can be shared by several clients.
+type Limiter struct {
+	bucket *rate.Limiter
+	slots  chan struct{}
+	routes []routeLimiter
+}
+
+type routeLimiter struct {
+	pattern string
+	limiter *Limiter
+}
+
+// NewLimiter allows rps requests per second with bursts of burst requests and maxInFlight
+// concurrent requests. Zero rps or maxInFlight means no limit.
+func NewLimiter(rps float64, burst int, maxInFlight int) *Limiter {
+	l := &Limiter{bucket: rate.NewLimiter(rate.Inf, 0)}
+	if rps > 0 {
+		if burst < 1 {
+			burst = 1
+		}
+		l.bucket = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+	if maxInFlight > 0 {
+		l.slots = make(chan struct{}, maxInFlight)
+	}
+	return l
+}
+
+// Route limits requests whose URL path matches pattern (see path.Match), like "/api/collections/*/auth-with-password",
+// with their own limits instead of l's. Routes are matched in the order they were added.
+func (l *Limiter) Route(pattern string, rps float64, burst int, maxInFlight int) *Limiter {
+	l.routes = append(l.routes, routeLimiter{pattern: pattern, limiter: NewLimiter(rps, burst, maxInFlight)})
+	return l
+}
+
+func (l *Limiter) route(urlPath string) *Limiter {
+	for _, r := range l.routes {
+		if ok, _ := path.Match(r.pattern, urlPath); ok {
+			return r.limiter
+		}
+	}
+	return l
+}
+
+// acquire waits for a token and a free slot, the returned func frees the slot.
+func (l *Limiter) acquire(ctx context.Context, urlPath string) (func(), error) {
+	l = l.route(urlPath)
+	if err := l.bucket.Wait(ctx); err != nil {
+		return nil, err
+	}
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// WithLimiter throttles all requests of the client, including authorization and realtime requests.
+func WithLimiter(limiter *Limiter) ClientOption {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+type limitTransport struct {
+	base    http.RoundTripper
+	limiter *Limiter
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.acquire(req.Context(), req.URL.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,113 @@
+package pocketbase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestWithLimiter(t *testing.T) {
+	tests := []struct {
+		name            string
+		limiter         *Limiter
+		collection      string
+		requests        int
+		wantMaxInFlight int32
+		wantMinDuration time.Duration
+	}{
+		{
+			name:            "Max in flight",
+			limiter:         NewLimiter(0, 0, 2),
+			collection:      "posts",
+			requests:        10,
+			wantMaxInFlight: 2,
+		},
+		{
+			name:            "Rate",
+			limiter:         NewLimiter(20, 1, 0),
+			collection:      "posts",
+			requests:        5,
+			wantMinDuration: 150 * time.Millisecond,
+		},
+		{
+			name:            "Route override",
+			limiter:         NewLimiter(0, 0, 4).Route("/api/collections/slow/*", 0, 0, 1),
+			collection:      "slow",
+			requests:        6,
+			wantMaxInFlight: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inFlight, maxInFlight atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					current := maxInFlight.Load()
+					if n <= current || maxInFlight.CompareAndSwap(current, n) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				_, _ = w.Write([]byte(`{"page": 1, "items": []}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, WithLimiter(tt.limiter))
+			start := time.Now()
+			var g errgroup.Group
+			for i := 0; i < tt.requests; i++ {
+				g.Go(func() error {
+					_, err := client.List(tt.collection, ParamsList{})
+					return err
+				})
+			}
+			assert.NoError(t, g.Wait())
+
+			if tt.wantMaxInFlight > 0 {
+				assert.Equal(t, tt.wantMaxInFlight, maxInFlight.Load())
+			}
+			assert.GreaterOrEqual(t, time.Since(start), tt.wantMinDuration)
+		})
+	}
+}
+
+func TestLimiter_SharedByClients(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		paths []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"page": 1, "items": []}`))
+	}))
+	defer server.Close()
+
+	limiter := NewLimiter(10, 1, 0)
+	first := NewClient(server.URL, WithLimiter(limiter))
+	second := CollectionSet[map[string]any](NewClient(server.URL, WithLimiter(limiter)), "posts")
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		_, err := first.List("posts", ParamsList{})
+		assert.NoError(t, err)
+		_, err = second.List(ParamsList{})
+		assert.NoError(t, err)
+	}
+	// 4 requests with a single token bucket of 10 rps and burst 1
+	assert.GreaterOrEqual(t, time.Since(start), 250*time.Millisecond)
+	assert.Len(t, paths, 4)
+	for _, p := range paths {
+		assert.True(t, strings.HasSuffix(p, "/posts/records"), p)
+	}
+}
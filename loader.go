@@ -0,0 +1,197 @@
+package pocketbase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrRecordNotFound = errors.New("record not found")
+
+type LoaderOptions struct {
+	// Wait is how long ids are collected before they are fetched, 2ms by default.
+	Wait time.Duration
+	// MaxBatch is the most ids fetched by a single request, 100 by default and at most 500,
+	// the largest page PocketBase returns. A batch is fetched right away once it is full.
+	MaxBatch int
+	// MaxFilterLength splits batches whose id filter would be longer, 3000 by default,
+	// to keep the request URL under common server and proxy limits.
+	MaxFilterLength int
+}
+
+// Loader batches One calls: ids requested within a short window are fetched
+// with a single List request filtered by id='a' || id='b'.
+type Loader[T any] struct {
+	collection Collection[T]
+	opts       LoaderOptions
+
+	mu      sync.Mutex
+	pending *loaderBatch[T]
+}
+
+type loaderBatch[T any] struct {
+	ids     []string
+	waiters map[string][]chan loaderResult[T]
+	timer   *time.Timer
+}
+
+type loaderResult[T any] struct {
+	record T
+	err    error
+}
+
+// NewLoader returns a Loader for the collection, e.g. to resolve relations without N+1 requests.
+func (c Collection[T]) NewLoader(opts LoaderOptions) *Loader[T] {
+	if opts.Wait <= 0 {
+		opts.Wait = 2 * time.Millisecond
+	}
+	if opts.MaxBatch <= 0 {
+		opts.MaxBatch = 100
+	}
+	if opts.MaxBatch > maxPageSize {
+		opts.MaxBatch = maxPageSize
+	}
+	if opts.MaxFilterLength <= 0 {
+		opts.MaxFilterLength = 3000
+	}
+	return &Loader[T]{collection: c, opts: opts}
+}
+
+// Load returns the record with id, errors.Is(err, ErrRecordNotFound) if it doesn't exist or isn't visible.
+func (l *Loader[T]) Load(id string) (T, error) {
+	result := <-l.enqueue(id)
+	return result.record, result.err
+}
+
+// LoadMany loads records in the order of ids, the first failure is returned.
+func (l *Loader[T]) LoadMany(ids ...string) ([]T, error) {
+	results := make([]<-chan loaderResult[T], len(ids))
+	for i, id := range ids {
+		results[i] = l.enqueue(id)
+	}
+
+	records := make([]T, len(ids))
+	var err error
+	for i, ch := range results {
+		result := <-ch
+		records[i] = result.record
+		if result.err != nil && err == nil {
+			err = result.err
+		}
+	}
+	return records, err
+}
+
+func (l *Loader[T]) enqueue(id string) <-chan loaderResult[T] {
+	ch := make(chan loaderResult[T], 1)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.pending
+	if b == nil {
+		b = &loaderBatch[T]{waiters: map[string][]chan loaderResult[T]{}}
+		b.timer = time.AfterFunc(l.opts.Wait, func() { l.dispatch(b) })
+		l.pending = b
+	}
+	if _, ok := b.waiters[id]; !ok {
+		b.ids = append(b.ids, id)
+	}
+	b.waiters[id] = append(b.waiters[id], ch)
+
+	if len(b.ids) >= l.opts.MaxBatch {
+		b.timer.Stop()
+		l.pending = nil
+		go l.fetch(b)
+	}
+	return ch
+}
+
+func (l *Loader[T]) dispatch(b *loaderBatch[T]) {
+	l.mu.Lock()
+	if l.pending != b {
+		// already dispatched as a full batch
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+
+	l.fetch(b)
+}
+
+func (l *Loader[T]) fetch(b *loaderBatch[T]) {
+	var wg sync.WaitGroup
+	for _, filter := range splitIDFilters(b.ids, l.opts.MaxFilterLength) {
+		wg.Add(1)
+		go func(filter idFilter) {
+			defer wg.Done()
+			l.fetchFilter(b, filter)
+		}(filter)
+	}
+	wg.Wait()
+}
+
+func (l *Loader[T]) fetchFilter(b *loaderBatch[T], filter idFilter) {
+	response, err := listWithIDs[T](l.collection.Client, l.collection.Name, ParamsList{
+		Page:    1,
+		Size:    len(filter.ids),
+		Filters: filter.filter,
+	})
+
+	found := map[string]T{}
+	for _, item := range response.Items {
+		found[item.ID] = item.Record
+	}
+	for _, id := range filter.ids {
+		result := loaderResult[T]{err: err}
+		if err == nil {
+			record, ok := found[id]
+			result.record = record
+			if !ok {
+				result.err = fmt.Errorf("[loader] %s %s, err %w", l.collection.Name, id, ErrRecordNotFound)
+			}
+		}
+		for _, ch := range b.waiters[id] {
+			ch <- result
+		}
+	}
+}
+
+type idFilter struct {
+	filter string
+	ids    []string
+}
+
+// splitIDFilters joins id conditions with || into filters not longer than maxLength,
+// a single condition is never split.
+func splitIDFilters(ids []string, maxLength int) []idFilter {
+	var (
+		filters []idFilter
+		current idFilter
+		b       strings.Builder
+	)
+	flush := func() {
+		if len(current.ids) > 0 {
+			current.filter = b.String()
+			filters = append(filters, current)
+		}
+		current = idFilter{}
+		b.Reset()
+	}
+	for _, id := range ids {
+		cond := FilterEq("id", id)
+		if len(current.ids) > 0 && b.Len()+len(" || ")+len(cond) > maxLength {
+			flush()
+		}
+		if len(current.ids) > 0 {
+			b.WriteString(" || ")
+		}
+		b.WriteString(cond)
+		current.ids = append(current.ids, id)
+	}
+	flush()
+	return filters
+}
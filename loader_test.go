@@ -0,0 +1,138 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoader(t *testing.T) {
+	var lists atomic.Int32
+	client := NewClient(defaultURL, WithAfterSend(func(op Operation, _ *http.Request, _ *http.Response, _ error) {
+		if op.Name == OperationList {
+			lists.Add(1)
+		}
+	}))
+	posts := CollectionSet[map[string]any](client, migrations.PostsPublic)
+
+	field := "loader_" + time.Now().Format(time.StampMilli)
+	var ids []string
+	for i := 0; i < 3; i++ {
+		created, err := posts.Create(map[string]any{"field": field})
+		require.NoError(t, err)
+		ids = append(ids, created.ID)
+	}
+
+	tests := []struct {
+		name      string
+		opts      LoaderOptions
+		ids       []string
+		wantLists int32
+		wantErr   bool
+	}{
+		{
+			name:      "Concurrent loads share a request",
+			ids:       ids,
+			wantLists: 1,
+		},
+		{
+			name:      "Duplicates are fetched once",
+			ids:       []string{ids[0], ids[1], ids[0], ids[0]},
+			wantLists: 1,
+		},
+		{
+			name:      "Missing record",
+			ids:       []string{ids[0], "non_existing_id"},
+			wantLists: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "Split by filter length",
+			opts:      LoaderOptions{MaxFilterLength: len(FilterEq("id", ids[0]))},
+			ids:       ids,
+			wantLists: 3,
+		},
+		{
+			name:      "Split by batch size",
+			opts:      LoaderOptions{MaxBatch: 2, Wait: time.Second},
+			ids:       ids,
+			wantLists: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lists.Store(0)
+			loader := posts.NewLoader(tt.opts)
+
+			var wg sync.WaitGroup
+			var failed atomic.Int32
+			for _, id := range tt.ids {
+				wg.Add(1)
+				go func(id string) {
+					defer wg.Done()
+					record, err := loader.Load(id)
+					if err != nil {
+						failed.Add(1)
+						assert.ErrorIs(t, err, ErrRecordNotFound)
+						return
+					}
+					assert.Equal(t, id, record["id"])
+					assert.Equal(t, field, record["field"])
+				}(id)
+			}
+			wg.Wait()
+			assert.Equal(t, tt.wantErr, failed.Load() > 0)
+			assert.Equal(t, tt.wantLists, lists.Load())
+		})
+	}
+
+	t.Run("LoadMany keeps order", func(t *testing.T) {
+		loader := posts.NewLoader(LoaderOptions{})
+		records, err := loader.LoadMany(ids[2], ids[0], ids[1])
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []any{ids[2], ids[0], ids[1]}, []any{records[0]["id"], records[1]["id"], records[2]["id"]})
+	})
+}
+
+func TestLoader_MaxBatch(t *testing.T) {
+	// PocketBase returns at most 500 records per page, whatever perPage asks for
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		ids := regexp.MustCompile(`id='([^']+)'`).FindAllStringSubmatch(r.URL.Query().Get("filter"), -1)
+		if len(ids) > 500 {
+			ids = ids[:500]
+		}
+		items := make([]map[string]any, 0, len(ids))
+		for _, id := range ids {
+			items = append(items, map[string]any{"id": id[1]})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"page": 1, "totalPages": 1, "items": items})
+	}))
+	defer server.Close()
+
+	posts := CollectionSet[map[string]any](NewClient(server.URL), "posts")
+	loader := posts.NewLoader(LoaderOptions{MaxBatch: 1000, MaxFilterLength: 1 << 20})
+
+	ids := make([]string, 600)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("id%013d", i)
+	}
+	records, err := loader.LoadMany(ids...)
+	require.NoError(t, err)
+	for i, record := range records {
+		assert.Equal(t, ids[i], record["id"])
+	}
+	assert.Equal(t, int32(2), requests.Load())
+}
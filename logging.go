@@ -0,0 +1,158 @@
+package pocketbase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const redacted = "[REDACTED]"
+
+// WithLogger logs through logger instead of slog.Default(). Requests are logged at debug level with
+// their operation, collection, status and duration, failures at warn level. Tokens and passwords are redacted.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// setupLogging routes resty logs to the client logger; WithDebug dumps are logged at info level.
+func (c *Client) setupLogging() {
+	c.client.SetLogger(restyLogger{c.logger})
+	if !c.debug {
+		return
+	}
+	c.client.SetDebug(true)
+	c.client.OnRequestLog(func(l *resty.RequestLog) error {
+		redactHeader(l.Header)
+		l.Body = redactBody(l.Body)
+		return nil
+	})
+	c.client.OnResponseLog(func(l *resty.ResponseLog) error {
+		redactHeader(l.Header)
+		l.Body = redactBody(l.Body)
+		return nil
+	})
+}
+
+type restyLogger struct {
+	logger *slog.Logger
+}
+
+func (l restyLogger) Errorf(format string, v ...any) {
+	l.logger.Error(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (l restyLogger) Warnf(format string, v ...any) {
+	l.logger.Warn(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func (l restyLogger) Debugf(format string, v ...any) {
+	l.logger.Info(strings.TrimSpace(fmt.Sprintf(format, v...)))
+}
+
+func redactHeader(header http.Header) {
+	for _, key := range []string{"Authorization", "Cookie", "Set-Cookie"} {
+		if header.Get(key) != "" {
+			header.Set(key, redacted)
+		}
+	}
+}
+
+// redactBody replaces secrets in JSON bodies, other bodies are returned as is.
+func redactBody(body string) string {
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return body
+	}
+	if !redactValue(value) {
+		return body
+	}
+	data, err := json.MarshalIndent(value, "", "   ")
+	if err != nil {
+		return redacted
+	}
+	return string(data)
+}
+
+func redactValue(value any) (changed bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isSecret(key) {
+				if item != "" && item != nil {
+					v[key] = redacted
+					changed = true
+				}
+				continue
+			}
+			changed = redactValue(item) || changed
+		}
+	case []any:
+		for _, item := range v {
+			changed = redactValue(item) || changed
+		}
+	}
+	return changed
+}
+
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "token") || strings.Contains(key, "secret")
+}
+
+type logTransport struct {
+	base   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *logTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+
+	level := slog.LevelDebug
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		level = slog.LevelWarn
+	}
+	ctx := req.Context()
+	if !t.logger.Enabled(ctx, level) {
+		return resp, err
+	}
+
+	op, _ := OperationFromContext(ctx)
+	attrs := []slog.Attr{
+		slog.String("operation", op.Name),
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if op.Collection != "" {
+		attrs = append(attrs, slog.String("collection", op.Collection))
+	}
+	if op.RecordID != "" {
+		attrs = append(attrs, slog.String("record_id", op.RecordID))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("err", err))
+		t.logger.LogAttrs(ctx, level, "pocketbase request failed", attrs...)
+		return resp, err
+	}
+	attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	t.logger.LogAttrs(ctx, level, "pocketbase request", attrs...)
+	return resp, err
+}
+
+// logError logs err unless it was caused by ctx being done, e.g. by Unsubscribe.
+func (c *Client) logError(ctx context.Context, msg string, err error, attrs ...any) {
+	if ctx != nil && ctx.Err() != nil {
+		c.logger.Debug(msg, append(attrs, "err", err)...)
+		return
+	}
+	c.logger.Error(msg, append(attrs, "err", err)...)
+}
@@ -0,0 +1,130 @@
+package pocketbase
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want map[string]any
+	}{
+		{
+			name: "Credentials",
+			body: `{"identity": "admin@admin.com", "password": "secret"}`,
+			want: map[string]any{"identity": "admin@admin.com", "password": redacted},
+		},
+		{
+			name: "Nested token",
+			body: `{"token": "abc", "record": {"id": "1", "passwordConfirm": "secret", "tokenKey": ""}}`,
+			want: map[string]any{"token": redacted, "record": map[string]any{"id": "1", "passwordConfirm": redacted, "tokenKey": ""}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]any
+			require.NoError(t, json.Unmarshal([]byte(redactBody(tt.body)), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, `{"field": "value"}`, redactBody(`{"field": "value"}`))
+	assert.Equal(t, "not json", redactBody("not json"))
+}
+
+func TestWithLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/admins/auth-with-password":
+			_, _ = w.Write([]byte(`{"token": "secret-token"}`))
+		case "/api/collections/posts/records":
+			_, _ = w.Write([]byte(`{"page": 1, "items": []}`))
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(server.URL,
+		WithAdminEmailPassword("admin@admin.com", "secret-password"),
+		WithLogger(logger),
+		WithRetryPolicy(RetryPolicy{}),
+	)
+
+	_, err := client.List("posts", ParamsList{})
+	require.NoError(t, err)
+	_, err = client.List("broken", ParamsList{})
+	require.Error(t, err)
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 3)
+	assert.Equal(t, "auth", records[0]["operation"])
+	assert.Equal(t, "DEBUG", records[1]["level"])
+	assert.Equal(t, "list", records[1]["operation"])
+	assert.Equal(t, "posts", records[1]["collection"])
+	assert.Equal(t, float64(http.StatusOK), records[1]["status"])
+	assert.Contains(t, records[1], "duration")
+	assert.Equal(t, "WARN", records[2]["level"])
+	assert.Equal(t, float64(http.StatusBadGateway), records[2]["status"])
+}
+
+func TestWithDebug_Redacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/admins/auth-with-password" {
+			_, _ = w.Write([]byte(`{"token": "secret-token"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"page": 1, "items": []}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	client := NewClient(server.URL,
+		WithAdminEmailPassword("admin@admin.com", "secret-password"),
+		WithLogger(slog.New(slog.NewTextHandler(&out, nil))),
+		WithDebug(),
+	)
+	_, err := client.List("posts", ParamsList{})
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "admin@admin.com")
+	assert.Contains(t, out.String(), redacted)
+	assert.NotContains(t, out.String(), "secret-password")
+	assert.NotContains(t, out.String(), "secret-token")
+}
+
+func TestCreateErrorRedaction(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient(defaultURL, WithLogger(logger), WithDebug())
+
+	// invalid email, so the create fails
+	_, err := client.Create("users", map[string]any{
+		"email":           "not-an-email",
+		"password":        "secret-password",
+		"passwordConfirm": "secret-password",
+	})
+	require.Error(t, err)
+	logger.Error("create failed", "err", err)
+
+	assert.NotContains(t, err.Error(), "secret-password")
+	assert.Contains(t, out.String(), "create failed")
+	assert.NotContains(t, out.String(), "secret-password")
+}
@@ -0,0 +1,90 @@
+package pocketbase
+
+import (
+	"context"
+	"net/http"
+)
+
+// Operation names of Operation.Name.
+const (
+	OperationCreate    = "create"
+	OperationUpdate    = "update"
+	OperationDelete    = "delete"
+	OperationList      = "list"
+	OperationOne       = "one"
+	OperationAuth      = "auth"
+	OperationBatch     = "batch"
+	OperationRealtime  = "realtime"
+	OperationSubscribe = "subscribe"
+)
+
+// Operation describes the client call a request is sent for,
+// Collection and RecordID are empty when the call has none.
+type Operation struct {
+	Name       string
+	Collection string
+	RecordID   string
+}
+
+type operationKey struct{}
+
+func withOperation(ctx context.Context, op Operation) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// OperationFromContext returns the operation of a request, e.g. req.Context() inside a Middleware.
+func OperationFromContext(ctx context.Context) (Operation, bool) {
+	op, ok := ctx.Value(operationKey{}).(Operation)
+	return op, ok
+}
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps every request of the client. It can change the request, inspect the response
+// or short-circuit the call by returning without calling next.
+// Middlewares run once per call, outside of retries and rate limits.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// WithMiddleware appends middlewares to the chain, the first one added sees requests first.
+func WithMiddleware(middlewares ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithBeforeSend calls fn before every request is sent, a non-nil error cancels the request.
+func WithBeforeSend(fn func(op Operation, req *http.Request) error) ClientOption {
+	return WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			op, _ := OperationFromContext(req.Context())
+			if err := fn(op, req); err != nil {
+				return nil, err
+			}
+			return next.RoundTrip(req)
+		})
+	})
+}
+
+// WithAfterSend calls fn with the outcome of every request.
+func WithAfterSend(fn func(op Operation, req *http.Request, resp *http.Response, err error)) ClientOption {
+	return WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			op, _ := OperationFromContext(req.Context())
+			fn(op, req, resp, err)
+			return resp, err
+		})
+	})
+}
+
+func (c *Client) wrapMiddlewares(transport http.RoundTripper) http.RoundTripper {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		transport = c.middlewares[i](transport)
+	}
+	return transport
+}
@@ -0,0 +1,170 @@
+package pocketbase
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	var serverCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverCalls.Add(1)
+		assert.Equal(t, "tenant-a", r.Header.Get("X-Tenant"))
+		switch r.Method {
+		case http.MethodPost:
+			_, _ = w.Write([]byte(`{"id": "new"}`))
+		case http.MethodGet:
+			_, _ = w.Write([]byte(`{"id": "abc"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	var (
+		mu    sync.Mutex
+		order []string
+		ops   []Operation
+	)
+	record := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	tenant := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set("X-Tenant", "tenant-a")
+			return next.RoundTrip(req)
+		})
+	}
+	// short-circuits lists with a canned response
+	cached := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if op, _ := OperationFromContext(req.Context()); op.Name == OperationList {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"page": 1, "totalItems": 1, "items": [{"id": "cached"}]}`)),
+					Request:    req,
+				}, nil
+			}
+			return next.RoundTrip(req)
+		})
+	}
+
+	client := NewClient(server.URL,
+		WithMiddleware(record("first"), record("second")),
+		WithMiddleware(tenant, cached),
+		WithAfterSend(func(op Operation, req *http.Request, resp *http.Response, err error) {
+			assert.NoError(t, err)
+			mu.Lock()
+			ops = append(ops, op)
+			mu.Unlock()
+		}),
+	)
+	posts := CollectionSet[map[string]any](client, "posts")
+
+	_, err := posts.Create(map[string]any{"field": "value"})
+	require.NoError(t, err)
+	_, err = posts.One("abc")
+	require.NoError(t, err)
+	require.NoError(t, posts.Delete("abc"))
+	list, err := posts.List(ParamsList{})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "cached", list.Items[0]["id"])
+
+	assert.Equal(t, int32(3), serverCalls.Load())
+	assert.Equal(t, []string{"first", "second", "first", "second", "first", "second", "first", "second"}, order)
+	assert.Equal(t, []Operation{
+		{Name: OperationCreate, Collection: "posts"},
+		{Name: OperationOne, Collection: "posts", RecordID: "abc"},
+		{Name: OperationDelete, Collection: "posts", RecordID: "abc"},
+	}, ops)
+}
+
+func TestWithBeforeSend(t *testing.T) {
+	var serverCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverCalls.Add(1)
+		assert.Equal(t, "signature", r.Header.Get("X-Signature"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	errReadOnly := errors.New("read only")
+	client := NewClient(server.URL, WithBeforeSend(func(op Operation, req *http.Request) error {
+		if op.Name == OperationDelete {
+			return errReadOnly
+		}
+		req.Header.Set("X-Signature", "signature")
+		return nil
+	}))
+
+	assert.NoError(t, client.Update("posts", "abc", map[string]any{"field": "value"}))
+	assert.ErrorIs(t, client.Delete("posts", "abc"), errReadOnly)
+	assert.Equal(t, int32(1), serverCalls.Load())
+}
+
+func TestWithMiddleware_RejectRealtime(t *testing.T) {
+	var serverCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverCalls.Add(1)
+	}))
+	defer server.Close()
+
+	errRejected := errors.New("realtime is not allowed")
+	tests := []struct {
+		name    string
+		option  ClientOption
+		wantErr error
+	}{
+		{
+			name: "Before send",
+			option: WithBeforeSend(func(op Operation, req *http.Request) error {
+				if op.Name == OperationRealtime {
+					return errRejected
+				}
+				return nil
+			}),
+			wantErr: errRejected,
+		},
+		{
+			name: "Short-circuit response",
+			option: WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+				return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: http.StatusForbidden,
+						Header:     http.Header{},
+						Body:       io.NopCloser(strings.NewReader(`{"message": "forbidden"}`)),
+						Request:    req,
+					}, nil
+				})
+			}),
+			wantErr: ErrInvalidResponse,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(server.URL, tt.option)
+			_, err := CollectionSet[map[string]any](client, "posts").Subscribe()
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+	assert.Equal(t, int32(0), serverCalls.Load())
+}
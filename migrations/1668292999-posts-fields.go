@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/daos"
+	m "github.com/pocketbase/pocketbase/migrations"
+	"github.com/pocketbase/pocketbase/models/schema"
+)
+
+// Number and multi-select fields, used to test update modifiers.
+func init() {
+	m.Register(func(db dbx.Builder) error {
+		dao := daos.New(db)
+		collection, err := dao.FindCollectionByNameOrId(PostsPublic)
+		if err != nil {
+			return err
+		}
+
+		if collection.Schema.GetFieldByName(FieldCount) == nil {
+			collection.Schema.AddField(&schema.SchemaField{
+				Name:    FieldCount,
+				Type:    schema.FieldTypeNumber,
+				Options: &schema.NumberOptions{},
+			})
+		}
+		if collection.Schema.GetFieldByName(FieldTags) == nil {
+			collection.Schema.AddField(&schema.SchemaField{
+				Name: FieldTags,
+				Type: schema.FieldTypeSelect,
+				Options: &schema.SelectOptions{
+					MaxSelect: 3,
+					Values:    []string{"a", "b", "c"},
+				},
+			})
+		}
+
+		return dao.SaveCollection(collection)
+	}, func(db dbx.Builder) error {
+		return nil
+	})
+}
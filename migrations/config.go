@@ -7,4 +7,6 @@ const (
 	PostsPublic        = "posts_public"
 	AdminEmailPassword = "admin@admin.com"
 	UserEmailPassword  = "user@user.com"
+	FieldCount         = "count"
+	FieldTags          = "tags"
 )
@@ -0,0 +1,235 @@
+package pocketbase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// Mirror is a local in-memory copy of a collection kept in sync by realtime events.
+type Mirror[T any] struct {
+	collection Collection[T]
+	params     ParamsList
+	stream     *Stream[T]
+
+	mu    sync.RWMutex
+	items map[string]T
+
+	callbacksMu sync.RWMutex
+	callbacks   []func(Event[T])
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Mirror lists all records matching params and keeps them up to date until ctx is done or Close is called.
+// Records are re-listed after every realtime reconnect, as events may have been missed in the meantime.
+// The initial load and the resyncs are sent with ctx.
+func (c Collection[T]) Mirror(ctx context.Context, params ParamsList) (*Mirror[T], error) {
+	m := &Mirror[T]{
+		collection: Collection[T]{c.Client.WithContext(ctx), c.Name},
+		params:     params,
+		items:      map[string]T{},
+		done:       make(chan struct{}),
+	}
+
+	stream, err := m.collection.SubscribeWith(SubscribeOptions{
+		ReconnectStrategy: &backoff.ZeroBackOff{},
+		OnReconnect: func() {
+			if err := m.resync(); err != nil {
+				m.collection.logger.Error("mirror resync failed", "collection", m.collection.Name, "err", err)
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.stream = stream
+	select {
+	case <-stream.Ready():
+	case <-ctx.Done():
+		stream.Unsubscribe()
+		return nil, fmt.Errorf("[mirror] can't subscribe, err %w", ctx.Err())
+	}
+	events := stream.Events()
+
+	// events received while listing wait in the stream and are applied afterwards
+	if err := m.resync(); err != nil {
+		stream.Unsubscribe()
+		return nil, err
+	}
+
+	go func() {
+		for e := range events {
+			m.apply(e)
+		}
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			m.Close()
+		case <-m.done:
+		}
+	}()
+
+	return m, nil
+}
+
+// Get returns the record with the given id.
+func (m *Mirror[T]) Get(id string) (T, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	item, ok := m.items[id]
+	return item, ok
+}
+
+// All returns a snapshot of all records keyed by id.
+func (m *Mirror[T]) All() map[string]T {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	items := make(map[string]T, len(m.items))
+	for id, item := range m.items {
+		items[id] = item
+	}
+	return items
+}
+
+// Filter returns all records for which fn returns true.
+func (m *Mirror[T]) Filter(fn func(T) bool) []T {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var items []T
+	for _, item := range m.items {
+		if fn(item) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (m *Mirror[T]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.items)
+}
+
+// OnChange registers fn to be called after every change applied to the mirror.
+// Changes found during a resync are reported as create, update and delete events too.
+func (m *Mirror[T]) OnChange(fn func(Event[T])) {
+	m.callbacksMu.Lock()
+	defer m.callbacksMu.Unlock()
+	m.callbacks = append(m.callbacks, fn)
+}
+
+// Close stops syncing; the last known records stay available.
+func (m *Mirror[T]) Close() {
+	m.closeOnce.Do(func() {
+		close(m.done)
+		m.stream.Unsubscribe()
+	})
+}
+
+func (m *Mirror[T]) resync() error {
+	items := map[string]T{}
+	params := m.params
+	params.Page = 1
+	if params.Size <= 0 {
+		params.Size = maxPageSize
+	}
+	for {
+		response, err := listWithIDs[T](m.collection.Client, m.collection.Name, params)
+		if err != nil {
+			return fmt.Errorf("[mirror] can't list records, err %w", err)
+		}
+		for _, item := range response.Items {
+			items[item.ID] = item.Record
+		}
+		if params.Page >= response.TotalPages {
+			break
+		}
+		params.Page++
+	}
+
+	var changes []Event[T]
+	m.mu.Lock()
+	for id, item := range items {
+		old, ok := m.items[id]
+		switch {
+		case !ok:
+			changes = append(changes, Event[T]{Action: ActionCreate, Record: item})
+		case !reflect.DeepEqual(old, item):
+			changes = append(changes, Event[T]{Action: ActionUpdate, Record: item})
+		}
+	}
+	for id, item := range m.items {
+		if _, ok := items[id]; !ok {
+			changes = append(changes, Event[T]{Action: ActionDelete, Record: item})
+		}
+	}
+	m.items = items
+	m.mu.Unlock()
+
+	for _, e := range changes {
+		m.notify(e)
+	}
+	return nil
+}
+
+func (m *Mirror[T]) apply(e Event[T]) {
+	id := e.id
+	if e.Error != nil {
+		m.collection.logger.Error("mirror can't decode event", "collection", m.collection.Name, "err", e.Error)
+		return
+	}
+
+	if !e.IsDelete() && m.params.Filters != "" {
+		matches, err := m.matches(id)
+		if err != nil {
+			m.collection.logger.Error("mirror filter check failed", "collection", m.collection.Name, "id", id, "err", err)
+			return
+		}
+		if !matches {
+			// the record may have been updated out of the filter
+			e.Action = ActionDelete
+		}
+	}
+
+	m.mu.Lock()
+	_, exists := m.items[id]
+	switch e.Action {
+	case ActionCreate, ActionUpdate:
+		m.items[id] = e.Record
+	case ActionDelete:
+		delete(m.items, id)
+	}
+	m.mu.Unlock()
+
+	if e.IsDelete() && !exists {
+		return
+	}
+	m.notify(e)
+}
+
+// matches checks server side whether the record still satisfies the mirror filter.
+func (m *Mirror[T]) matches(id string) (bool, error) {
+	response, err := listAs[Record](m.collection.Client, m.collection.Name, ParamsList{
+		Page:    1,
+		Size:    1,
+		Filters: "(" + m.params.Filters + ") && " + FilterEq("id", id),
+	})
+	if err != nil {
+		return false, fmt.Errorf("[mirror] can't check record filter, err %w", err)
+	}
+	return response.TotalItems > 0, nil
+}
+
+func (m *Mirror[T]) notify(e Event[T]) {
+	m.callbacksMu.RLock()
+	defer m.callbacksMu.RUnlock()
+	for _, fn := range m.callbacks {
+		fn(e)
+	}
+}
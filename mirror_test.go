@@ -0,0 +1,205 @@
+package pocketbase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollection_Mirror(t *testing.T) {
+	type post struct {
+		ID    string `json:"id"`
+		Field string `json:"field"`
+	}
+
+	client := NewClient(defaultURL)
+	field := "value_" + time.Now().Format(time.StampMilli)
+	collection := CollectionSet[post](client, migrations.PostsPublic)
+
+	// existing item must be loaded by the initial list
+	existing, err := collection.Create(post{Field: field})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mirror, err := collection.Mirror(ctx, ParamsList{})
+	require.NoError(t, err)
+
+	changes := make(chan Event[post], 10)
+	mirror.OnChange(func(e Event[post]) { changes <- e })
+
+	item, ok := mirror.Get(existing.ID)
+	assert.True(t, ok)
+	assert.Equal(t, field, item.Field)
+
+	waitChange := func(action EventAction) Event[post] {
+		select {
+		case e := <-changes:
+			assert.Equal(t, action, e.Action)
+			return e
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no %s change", action)
+		}
+		return Event[post]{}
+	}
+
+	t.Run("mirror create", func(t *testing.T) {
+		created, err := collection.Create(post{Field: field + "_created"})
+		require.NoError(t, err)
+		e := waitChange(ActionCreate)
+		assert.Equal(t, created.ID, e.Record.ID)
+
+		item, ok := mirror.Get(created.ID)
+		assert.True(t, ok)
+		assert.Equal(t, field+"_created", item.Field)
+		assert.Len(t, mirror.Filter(func(p post) bool { return p.ID == created.ID }), 1)
+	})
+
+	t.Run("mirror update", func(t *testing.T) {
+		require.NoError(t, collection.Update(existing.ID, post{Field: field + "_updated"}))
+		waitChange(ActionUpdate)
+
+		item, ok := mirror.Get(existing.ID)
+		assert.True(t, ok)
+		assert.Equal(t, field+"_updated", item.Field)
+	})
+
+	t.Run("mirror delete", func(t *testing.T) {
+		require.NoError(t, collection.Delete(existing.ID))
+		waitChange(ActionDelete)
+
+		_, ok := mirror.Get(existing.ID)
+		assert.False(t, ok)
+		assert.NotContains(t, mirror.All(), existing.ID)
+	})
+}
+
+func TestCollection_MirrorFilter(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := "value_" + time.Now().Format(time.StampMilli)
+	collection := CollectionSet[map[string]any](client, migrations.PostsPublic)
+
+	mirror, err := collection.Mirror(context.Background(), ParamsList{Filters: "field='" + field + "'"})
+	require.NoError(t, err)
+	defer mirror.Close()
+	assert.Equal(t, 0, mirror.Len())
+
+	changes := make(chan Event[map[string]any], 10)
+	mirror.OnChange(func(e Event[map[string]any]) { changes <- e })
+
+	_, err = collection.Create(map[string]any{"field": field + "_other"})
+	require.NoError(t, err)
+	matching, err := collection.Create(map[string]any{"field": field})
+	require.NoError(t, err)
+
+	select {
+	case e := <-changes:
+		assert.Equal(t, ActionCreate, e.Action)
+		assert.Equal(t, matching.ID, e.Record["id"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no create change")
+	}
+	assert.Equal(t, 1, mirror.Len())
+}
+
+func TestCollection_MirrorContext(t *testing.T) {
+	slow := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodGet && req.URL.Path != "/api/realtime" {
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-time.After(5 * time.Second):
+			}
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	collection := CollectionSet[map[string]any](NewClient(defaultURL, WithTransport(slow)), migrations.PostsPublic)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := collection.Mirror(ctx, ParamsList{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestCollection_MirrorOutage(t *testing.T) {
+	type post struct {
+		ID    string `json:"id"`
+		Field string `json:"field"`
+	}
+
+	// the server is unreachable for the mirror while down is set
+	var (
+		down  atomic.Bool
+		mu    sync.Mutex
+		conns []net.Conn
+	)
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if down.Load() {
+				return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("connection refused")}
+			}
+			conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+			if err == nil {
+				mu.Lock()
+				conns = append(conns, conn)
+				mu.Unlock()
+			}
+			return conn, err
+		},
+	}
+	client := NewClient(defaultURL,
+		WithTransport(transport),
+		WithRetryPolicy(RetryPolicy{}),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	collection := CollectionSet[post](client, migrations.PostsPublic)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mirror, err := collection.Mirror(ctx, ParamsList{})
+	require.NoError(t, err)
+	changes := make(chan Event[post], 100)
+	mirror.OnChange(func(e Event[post]) { changes <- e })
+
+	down.Store(true)
+	mu.Lock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+	conns = nil
+	mu.Unlock()
+
+	// created while the mirror can't receive events
+	created, err := CollectionSet[post](NewClient(defaultURL), migrations.PostsPublic).
+		Create(post{Field: "value_" + time.Now().Format(time.StampMilli)})
+	require.NoError(t, err)
+	time.Sleep(200 * time.Millisecond)
+	down.Store(false)
+
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case e := <-changes:
+			if e.Action == ActionCreate && e.Record.ID == created.ID {
+				_, ok := mirror.Get(created.ID)
+				assert.True(t, ok)
+				return
+			}
+		case <-timeout:
+			t.Fatal("mirror didn't resync after the outage")
+		}
+	}
+}
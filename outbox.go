@@ -0,0 +1,549 @@
+package pocketbase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrOutboxClosed = errors.New("outbox is closed")
+	// ErrNotIdempotent is returned by Outbox.Update for bodies with field modifiers, like Patch.Increment,
+	// which would apply twice when resent after a lost response.
+	ErrNotIdempotent = errors.New("update with field modifiers is not idempotent")
+)
+
+// Mutation is a write queued in an Outbox.
+type Mutation struct {
+	ID         string          `json:"id"`
+	Action     EventAction     `json:"action"`
+	Collection string          `json:"collection"`
+	RecordID   string          `json:"recordId"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	Queued     time.Time       `json:"queued"`
+	// Attempted is set once a create or delete was sent, its response may have been lost.
+	Attempted bool `json:"attempted,omitempty"`
+}
+
+// OutboxStore persists queued mutations, e.g. in a file (FileOutboxStore) or an SQLite table.
+type OutboxStore interface {
+	Append(m Mutation) error
+	// Pending returns the queued mutations in the order they were appended.
+	Pending() ([]Mutation, error)
+	// MarkAttempted sets Attempted of the mutation before it is sent.
+	MarkAttempted(id string) error
+	Remove(id string) error
+}
+
+type OutboxOptions struct {
+	// Store keeps the queue, a FileOutboxStore at Path if nil.
+	Store OutboxStore
+	Path  string
+	// Interval between replays while the server is unreachable, 5 seconds by default.
+	Interval time.Duration
+	// OnConflict is called with mutations the server rejected because of its current state:
+	// creating a record whose id already exists, updating or deleting a missing one. errors.Is(err, ErrConflict) matches err.
+	OnConflict func(m Mutation, err error)
+	// OnFailure is called with mutations rejected for other reasons, like failed validation or missing permissions.
+	OnFailure func(m Mutation, err error)
+}
+
+// Outbox queues Create, Update and Delete durably and sends them in order once the server is reachable.
+// Connection errors, 401, 429 and 5xx responses keep a mutation queued; mutations rejected otherwise are dropped
+// after calling OnConflict or OnFailure, so later ones are not blocked.
+// A resent create or delete whose earlier response was lost is recognized as applied, not as a conflict.
+type Outbox struct {
+	client *Client
+	store  OutboxStore
+	opts   OutboxOptions
+
+	replaying sync.Mutex
+	wake      chan struct{}
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewOutbox opens the queue and starts replaying it in the background, call Close to stop.
+func (c *Client) NewOutbox(opts OutboxOptions) (*Outbox, error) {
+	if opts.Store == nil {
+		if opts.Path == "" {
+			return nil, errors.New("[outbox] store or path is required")
+		}
+		store, err := NewFileOutboxStore(opts.Path)
+		if err != nil {
+			return nil, err
+		}
+		opts.Store = store
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	o := &Outbox{
+		client: c,
+		store:  opts.Store,
+		opts:   opts,
+		wake:   make(chan struct{}, 1),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go o.run()
+	o.notify()
+	return o, nil
+}
+
+// Create queues a record creation and returns its id, generated unless body has one,
+// so the record can be updated or referenced before it reaches the server.
+func (o *Outbox) Create(collection string, body any) (string, error) {
+	raw, err := o.client.codec.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("[outbox] can't marshal body, err %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := o.client.codec.Unmarshal(raw, &fields); err != nil {
+		return "", fmt.Errorf("[outbox] body must be a JSON object, err %w", err)
+	}
+
+	var id string
+	if value, ok := fields["id"]; ok {
+		_ = o.client.codec.Unmarshal(value, &id)
+	}
+	if id == "" {
+		id = newRecordID()
+		if fields["id"], err = o.client.codec.Marshal(id); err != nil {
+			return "", fmt.Errorf("[outbox] can't marshal id, err %w", err)
+		}
+		if raw, err = o.client.codec.Marshal(fields); err != nil {
+			return "", fmt.Errorf("[outbox] can't marshal body, err %w", err)
+		}
+	}
+
+	return id, o.enqueue(ActionCreate, collection, id, raw)
+}
+
+// Update queues a record update. Updates are resent when their response is lost,
+// so bodies with field modifiers are rejected with ErrNotIdempotent.
+func (o *Outbox) Update(collection string, id string, body any) error {
+	raw, err := o.client.codec.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("[outbox] can't marshal body, err %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := o.client.codec.Unmarshal(raw, &fields); err != nil {
+		return fmt.Errorf("[outbox] body must be a JSON object, err %w", err)
+	}
+	for field := range fields {
+		if strings.HasPrefix(field, "+") || strings.HasSuffix(field, "+") || strings.HasSuffix(field, "-") {
+			return fmt.Errorf("[outbox] field %s, err %w", field, ErrNotIdempotent)
+		}
+	}
+	return o.enqueue(ActionUpdate, collection, id, raw)
+}
+
+func (o *Outbox) Delete(collection string, id string) error {
+	return o.enqueue(ActionDelete, collection, id, nil)
+}
+
+// Len returns the number of queued mutations.
+func (o *Outbox) Len() (int, error) {
+	pending, err := o.store.Pending()
+	return len(pending), err
+}
+
+// Flush sends the queued mutations now, it returns the error that stopped the replay if any remain queued.
+func (o *Outbox) Flush(ctx context.Context) error {
+	return o.replay(ctx)
+}
+
+// Close stops the background replay, queued mutations stay in the store.
+func (o *Outbox) Close() error {
+	o.cancel()
+	<-o.done
+	return nil
+}
+
+func (o *Outbox) enqueue(action EventAction, collection, id string, body json.RawMessage) error {
+	if o.ctx.Err() != nil {
+		return ErrOutboxClosed
+	}
+	m := Mutation{
+		ID:         newRecordID(),
+		Action:     action,
+		Collection: collection,
+		RecordID:   id,
+		Body:       body,
+		Queued:     time.Now(),
+	}
+	if err := o.store.Append(m); err != nil {
+		return fmt.Errorf("[outbox] can't store mutation, err %w", err)
+	}
+	o.notify()
+	return nil
+}
+
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Outbox) run() {
+	defer close(o.done)
+
+	ticker := time.NewTicker(o.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-o.ctx.Done():
+			return
+		case <-o.wake:
+		case <-ticker.C:
+		}
+		if err := o.replay(o.ctx); err != nil && o.ctx.Err() == nil {
+			o.client.logger.Warn("outbox replay paused", "err", err)
+		}
+	}
+}
+
+func (o *Outbox) replay(ctx context.Context) error {
+	o.replaying.Lock()
+	defer o.replaying.Unlock()
+
+	pending, err := o.store.Pending()
+	if err != nil {
+		return fmt.Errorf("[outbox] can't read queue, err %w", err)
+	}
+	client := o.client.WithContext(ctx)
+	for _, m := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if m.Action != ActionUpdate && !m.Attempted {
+			if err := o.store.MarkAttempted(m.ID); err != nil {
+				return fmt.Errorf("[outbox] can't store mutation, err %w", err)
+			}
+		}
+
+		err := o.send(client, m)
+		switch {
+		case err == nil:
+			client.InvalidateCache(m.Collection, m.RecordID)
+		case errors.Is(err, ErrConflict):
+			if o.opts.OnConflict != nil {
+				o.opts.OnConflict(m, err)
+			}
+		case errors.Is(err, errPermanent):
+			if o.opts.OnFailure != nil {
+				o.opts.OnFailure(m, err)
+			}
+		default:
+			return err
+		}
+
+		if err := o.store.Remove(m.ID); err != nil {
+			return fmt.Errorf("[outbox] can't remove mutation, err %w", err)
+		}
+	}
+	return nil
+}
+
+// errPermanent marks responses that won't succeed when resent.
+var errPermanent = errors.New("mutation rejected")
+
+func (o *Outbox) send(c *Client, m Mutation) error {
+	if err := c.Authorize(); err != nil {
+		return err
+	}
+
+	path := c.url + "/api/collections/" + url.PathEscape(m.Collection) + "/records"
+	op := Operation{Collection: m.Collection, RecordID: m.RecordID}
+	method := http.MethodPost
+	switch m.Action {
+	case ActionCreate:
+		op.Name = OperationCreate
+	case ActionUpdate:
+		op.Name, method, path = OperationUpdate, http.MethodPatch, path+"/"+url.PathEscape(m.RecordID)
+	case ActionDelete:
+		op.Name, method, path = OperationDelete, http.MethodDelete, path+"/"+url.PathEscape(m.RecordID)
+	default:
+		return fmt.Errorf("[outbox] unknown action %s, err %w", m.Action, errPermanent)
+	}
+
+	request := c.request(op).SetHeader("Content-Type", "application/json")
+	if m.Body != nil {
+		request.SetBody([]byte(m.Body))
+	}
+	resp, err := request.Execute(method, path)
+	if err != nil {
+		return fmt.Errorf("[outbox] can't send %s request to pocketbase, err %w", m.Action, err)
+	}
+
+	code := resp.StatusCode()
+	if !resp.IsError() {
+		return nil
+	}
+	if code == http.StatusUnauthorized || code == http.StatusTooManyRequests || code == http.StatusRequestTimeout ||
+		code >= http.StatusInternalServerError {
+		return fmt.Errorf("[outbox] pocketbase returned status: %d, msg: %s, err %w", code, resp.String(), ErrInvalidResponse)
+	}
+
+	reason := errPermanent
+	if o.isConflict(m.Action, code, resp.Body()) {
+		reason = ErrConflict
+		if m.Attempted {
+			// the previous attempt may have been applied with its response lost
+			applied, err := o.applied(c, m)
+			if err != nil || applied {
+				return err
+			}
+		}
+	}
+	return fmt.Errorf("[outbox] %s %s/%s pocketbase returned status: %d, msg: %s, err %w",
+		m.Action, m.Collection, m.RecordID, code, resp.String(), reason)
+}
+
+// isConflict tells rejections caused by the record state apart from invalid mutations:
+// PocketBase answers 400 with an id error to a duplicate id and 404 for missing records.
+func (o *Outbox) isConflict(action EventAction, code int, body []byte) bool {
+	if action != ActionCreate {
+		return code == http.StatusNotFound
+	}
+	if code != http.StatusBadRequest {
+		return false
+	}
+	var response struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := o.client.codec.Unmarshal(body, &response); err != nil {
+		return false
+	}
+	_, ok := response.Data["id"]
+	return ok
+}
+
+// applied checks whether a conflicting create or delete is the effect of its own earlier attempt:
+// the created record exists or the deleted one is gone.
+func (o *Outbox) applied(c *Client, m Mutation) (bool, error) {
+	switch m.Action {
+	case ActionDelete:
+		return true, nil
+	case ActionCreate:
+	default:
+		return false, nil
+	}
+
+	resp, err := c.request(Operation{Name: OperationOne, Collection: m.Collection, RecordID: m.RecordID}).
+		SetHeader("Content-Type", "application/json").
+		Get(c.url + "/api/collections/" + url.PathEscape(m.Collection) + "/records/" + url.PathEscape(m.RecordID))
+	if err != nil {
+		return false, fmt.Errorf("[outbox] can't check created record, err %w", err)
+	}
+	switch code := resp.StatusCode(); {
+	case !resp.IsError():
+		return true, nil
+	case code == http.StatusNotFound || code == http.StatusForbidden:
+		return false, nil
+	default:
+		return false, fmt.Errorf("[outbox] pocketbase returned status: %d, msg: %s, err %w", code, resp.String(), ErrInvalidResponse)
+	}
+}
+
+const recordIDAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// newRecordID returns a random 15 characters id in the PocketBase format.
+// Bytes past the largest multiple of the alphabet length are skipped, so every character is equally likely.
+func newRecordID() string {
+	const limit = 256 - 256%len(recordIDAlphabet)
+	id := make([]byte, 0, 15)
+	b := make([]byte, 32)
+	for len(id) < cap(id) {
+		if _, err := rand.Read(b); err != nil {
+			panic(err)
+		}
+		for _, v := range b {
+			if int(v) < limit && len(id) < cap(id) {
+				id = append(id, recordIDAlphabet[int(v)%len(recordIDAlphabet)])
+			}
+		}
+	}
+	return string(id)
+}
+
+// FileOutboxStore keeps mutations in an append-only JSON lines log, synced to disk on every change.
+// Sent mutations are recorded as tombstones, the log is compacted once they outnumber the queued ones.
+type FileOutboxStore struct {
+	path string
+
+	mu         sync.Mutex
+	pending    []Mutation
+	tombstones int
+}
+
+// outboxEntry is a line of the FileOutboxStore log.
+type outboxEntry struct {
+	Mutation  *Mutation `json:"mutation,omitempty"`
+	Attempted string    `json:"attempted,omitempty"`
+	Removed   string    `json:"removed,omitempty"`
+}
+
+// outboxCompactAfter is the number of tombstones below which the log is not compacted.
+const outboxCompactAfter = 1000
+
+// NewFileOutboxStore opens or creates the queue file at path.
+// A truncated last line, left by a crash while appending, is ignored.
+func NewFileOutboxStore(path string) (*FileOutboxStore, error) {
+	s := &FileOutboxStore{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("[outbox] can't read %s, err %w", path, err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for line := 1; scanner.Scan(); line++ {
+		var entry outboxEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			if !bytes.HasSuffix(data, []byte("\n")) && bytes.HasSuffix(data, scanner.Bytes()) {
+				break
+			}
+			return nil, fmt.Errorf("[outbox] can't parse %s line %d, err %w", path, line, err)
+		}
+		s.apply(entry)
+	}
+
+	// compact, which also drops a truncated line so the next append starts on a new one
+	if err := s.write(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileOutboxStore) Append(m Mutation) error {
+	return s.log(outboxEntry{Mutation: &m})
+}
+
+func (s *FileOutboxStore) Pending() ([]Mutation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Mutation(nil), s.pending...), nil
+}
+
+func (s *FileOutboxStore) MarkAttempted(id string) error {
+	return s.log(outboxEntry{Attempted: id})
+}
+
+func (s *FileOutboxStore) Remove(id string) error {
+	if err := s.log(outboxEntry{Removed: id}); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tombstones < outboxCompactAfter || s.tombstones < len(s.pending) {
+		return nil
+	}
+	return s.write()
+}
+
+// log appends entry to the file and applies it to the pending mutations.
+func (s *FileOutboxStore) log(entry outboxEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	s.apply(entry)
+	return nil
+}
+
+func (s *FileOutboxStore) apply(entry outboxEntry) {
+	switch {
+	case entry.Mutation != nil:
+		s.pending = append(s.pending, *entry.Mutation)
+	case entry.Attempted != "":
+		for i := range s.pending {
+			if s.pending[i].ID == entry.Attempted {
+				s.pending[i].Attempted = true
+			}
+		}
+	case entry.Removed != "":
+		s.tombstones++
+		for i, m := range s.pending {
+			if m.ID != entry.Removed {
+				continue
+			}
+			if i == 0 {
+				// mutations are sent in order, so this is the common case
+				s.pending = s.pending[1:]
+			} else {
+				s.pending = append(s.pending[:i:i], s.pending[i+1:]...)
+			}
+			break
+		}
+	}
+}
+
+// write replaces the log with the pending mutations atomically.
+func (s *FileOutboxStore) write() error {
+	var buf bytes.Buffer
+	for i := range s.pending {
+		line, err := json.Marshal(outboxEntry{Mutation: &s.pending[i]})
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("[outbox] can't write queue, err %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("[outbox] can't write queue, err %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("[outbox] can't write queue, err %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("[outbox] can't write queue, err %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("[outbox] can't write queue, err %w", err)
+	}
+	s.tombstones = 0
+	return nil
+}
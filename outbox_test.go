@@ -0,0 +1,344 @@
+package pocketbase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutbox_Replay(t *testing.T) {
+	client := NewClient(defaultURL)
+	var (
+		mu        sync.Mutex
+		conflicts []Mutation
+		failures  []Mutation
+	)
+	outbox, err := client.NewOutbox(OutboxOptions{
+		Path:     filepath.Join(t.TempDir(), "outbox.jsonl"),
+		Interval: time.Hour,
+		OnConflict: func(m Mutation, err error) {
+			assert.ErrorIs(t, err, ErrConflict)
+			mu.Lock()
+			conflicts = append(conflicts, m)
+			mu.Unlock()
+		},
+		OnFailure: func(m Mutation, err error) {
+			mu.Lock()
+			failures = append(failures, m)
+			mu.Unlock()
+		},
+	})
+	require.NoError(t, err)
+	defer outbox.Close()
+
+	field := "outbox_" + time.Now().Format(time.StampMilli)
+	id, err := outbox.Create(migrations.PostsPublic, map[string]any{"field": field})
+	require.NoError(t, err)
+	require.Len(t, id, 15)
+	require.NoError(t, outbox.Update(migrations.PostsPublic, id, map[string]any{"field": field + "_updated"}))
+	require.NoError(t, outbox.Update(migrations.PostsPublic, "non_existing_id", map[string]any{"field": field}))
+	_, err = outbox.Create(migrations.PostsPublic, map[string]any{"id": id, "field": field})
+	require.NoError(t, err)
+	_, err = outbox.Create(migrations.PostsUser, map[string]any{"field": field})
+	require.NoError(t, err)
+
+	require.NoError(t, outbox.Flush(context.Background()))
+	pending, err := outbox.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
+
+	record, err := CollectionSet[map[string]any](client, migrations.PostsPublic).One(id)
+	require.NoError(t, err)
+	assert.Equal(t, field+"_updated", record["field"])
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, ActionUpdate, conflicts[0].Action)
+	assert.Equal(t, ActionCreate, conflicts[1].Action)
+	require.Len(t, failures, 1)
+	assert.Equal(t, migrations.PostsUser, failures[0].Collection)
+
+	require.NoError(t, client.Delete(migrations.PostsPublic, id))
+}
+
+func TestOutbox_LostResponse(t *testing.T) {
+	// the first create and delete reach the server, but their responses are lost
+	var lost sync.Map
+	transport := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := http.DefaultTransport.RoundTrip(req)
+		if err != nil || req.Method == http.MethodGet {
+			return resp, err
+		}
+		if _, seen := lost.LoadOrStore(req.Method, true); seen {
+			return resp, err
+		}
+		resp.Body.Close()
+		return nil, errors.New("connection reset by peer")
+	})
+	client := NewClient(defaultURL, WithTransport(transport), WithRetryPolicy(RetryPolicy{}))
+
+	var conflicts atomic.Int32
+	outbox, err := client.NewOutbox(OutboxOptions{
+		Path:       filepath.Join(t.TempDir(), "outbox.jsonl"),
+		Interval:   time.Hour,
+		OnConflict: func(Mutation, error) { conflicts.Add(1) },
+		OnFailure:  func(m Mutation, err error) { t.Errorf("%s failed: %v", m.Action, err) },
+	})
+	require.NoError(t, err)
+	defer outbox.Close()
+	posts := CollectionSet[map[string]any](NewClient(defaultURL), migrations.PostsPublic)
+
+	id, err := outbox.Create(migrations.PostsPublic, map[string]any{"field": "outbox_lost"})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, err := posts.One(id)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, outbox.Flush(context.Background()))
+
+	require.NoError(t, outbox.Delete(migrations.PostsPublic, id))
+	require.Eventually(t, func() bool {
+		_, err := posts.One(id)
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, outbox.Flush(context.Background()))
+
+	pending, err := outbox.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
+	assert.Equal(t, int32(0), conflicts.Load())
+}
+
+func TestOutbox_Offline(t *testing.T) {
+	var (
+		online atomic.Bool
+		mu     sync.Mutex
+		sent   []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !online.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		mu.Lock()
+		sent = append(sent, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	client := NewClient(server.URL, WithRetryPolicy(RetryPolicy{}))
+	opts := OutboxOptions{Path: path, Interval: 10 * time.Millisecond}
+
+	outbox, err := client.NewOutbox(opts)
+	require.NoError(t, err)
+	id, err := outbox.Create("posts", map[string]any{"field": "value"})
+	require.NoError(t, err)
+	require.NoError(t, outbox.Update("posts", id, map[string]any{"field": "updated"}))
+	require.NoError(t, outbox.Delete("posts", id))
+
+	assert.Error(t, outbox.Flush(context.Background()))
+	require.NoError(t, outbox.Close())
+	assert.ErrorIs(t, outbox.Delete("posts", id), ErrOutboxClosed)
+
+	// the queue survives a restart and is replayed in order once the server is back
+	outbox, err = client.NewOutbox(opts)
+	require.NoError(t, err)
+	defer outbox.Close()
+	pending, err := outbox.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 3, pending)
+
+	online.Store(true)
+	assert.Eventually(t, func() bool {
+		pending, err := outbox.Len()
+		return err == nil && pending == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"POST /api/collections/posts/records",
+		"PATCH /api/collections/posts/records/" + id,
+		"DELETE /api/collections/posts/records/" + id,
+	}, sent)
+}
+
+func TestOutbox_Update(t *testing.T) {
+	outbox, err := NewClient(defaultURL).NewOutbox(OutboxOptions{
+		Path:     filepath.Join(t.TempDir(), "outbox.jsonl"),
+		Interval: time.Hour,
+	})
+	require.NoError(t, err)
+	defer outbox.Close()
+
+	tests := []struct {
+		name    string
+		body    any
+		wantErr error
+	}{
+		{name: "Fields", body: NewPatch().Set("field", "value").Unset("other")},
+		{name: "Increment", body: NewPatch().Increment("count", 1), wantErr: ErrNotIdempotent},
+		{name: "Decrement", body: NewPatch().Decrement("count", 1), wantErr: ErrNotIdempotent},
+		{name: "Append", body: map[string]any{"tags+": []string{"a"}}, wantErr: ErrNotIdempotent},
+		{name: "Prepend", body: map[string]any{"+tags": []string{"a"}}, wantErr: ErrNotIdempotent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := outbox.Update("posts", "abc", tt.body)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOutbox_Unauthorized(t *testing.T) {
+	var (
+		requests   atomic.Int32
+		authorized atomic.Bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !authorized.Load() {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message": "The request requires valid record authorization token to be set."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	outbox, err := NewClient(server.URL, WithRetryPolicy(RetryPolicy{})).NewOutbox(OutboxOptions{
+		Path:      filepath.Join(t.TempDir(), "outbox.jsonl"),
+		Interval:  time.Hour,
+		OnFailure: func(m Mutation, err error) { t.Errorf("%s failed: %v", m.Action, err) },
+	})
+	require.NoError(t, err)
+	defer outbox.Close()
+
+	// an expired token keeps the mutation queued until it's replayed with a valid one
+	require.NoError(t, outbox.Delete("posts", "abc"))
+	require.Eventually(t, func() bool { return requests.Load() > 0 }, time.Second, time.Millisecond)
+	assert.Error(t, outbox.Flush(context.Background()))
+	pending, err := outbox.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 1, pending)
+
+	authorized.Store(true)
+	require.NoError(t, outbox.Flush(context.Background()))
+	pending, err = outbox.Len()
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
+}
+
+func TestNewRecordID(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		id := newRecordID()
+		assert.Len(t, id, 15)
+		assert.Empty(t, strings.Trim(id, recordIDAlphabet))
+	}
+}
+
+func TestFileOutboxStore(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		// ids left after appending c, marking it attempted and removing a
+		wantPending []string
+		wantErr     bool
+	}{
+		{
+			name:        "Missing file",
+			wantPending: []string{"c"},
+		},
+		{
+			name: "Queued mutations",
+			content: `{"mutation":{"id":"a","action":"create"}}` + "\n" +
+				`{"mutation":{"id":"b","action":"delete"}}` + "\n" +
+				`{"mutation":{"id":"x","action":"delete"}}` + "\n" +
+				`{"removed":"x"}` + "\n",
+			wantPending: []string{"b", "c"},
+		},
+		{
+			name:        "Truncated last line",
+			content:     `{"mutation":{"id":"a","action":"create"}}` + "\n" + `{"mutation":{"id":"b","act`,
+			wantPending: []string{"c"},
+		},
+		{
+			name:    "Corrupted line",
+			content: `{"mutation":{"id":"a","act` + "\n" + `{"mutation":{"id":"b","action":"delete"}}` + "\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "outbox.jsonl")
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			}
+
+			store, err := NewFileOutboxStore(path)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if err != nil {
+				return
+			}
+			require.NoError(t, store.Append(Mutation{ID: "c", Action: ActionCreate}))
+			require.NoError(t, store.MarkAttempted("c"))
+			require.NoError(t, store.Remove("a"))
+
+			reopened, err := NewFileOutboxStore(path)
+			require.NoError(t, err)
+			pending, err := reopened.Pending()
+			require.NoError(t, err)
+			var ids []string
+			for _, m := range pending {
+				ids = append(ids, m.ID)
+				assert.Equal(t, m.ID == "c", m.Attempted, m.ID)
+			}
+			assert.Equal(t, tt.wantPending, ids)
+		})
+	}
+}
+
+func TestFileOutboxStore_Compaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	store, err := NewFileOutboxStore(path)
+	require.NoError(t, err)
+
+	// a queue drained while new mutations arrive stays small on disk
+	for i := 0; i < 3*outboxCompactAfter; i++ {
+		require.NoError(t, store.Append(Mutation{ID: fmt.Sprint(i), Action: ActionDelete}))
+		require.NoError(t, store.Remove(fmt.Sprint(i)))
+	}
+	require.NoError(t, store.Append(Mutation{ID: "last", Action: ActionDelete}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, bytes.Count(data, []byte("\n")), 2*outboxCompactAfter+1)
+
+	reopened, err := NewFileOutboxStore(path)
+	require.NoError(t, err)
+	pending, err := reopened.Pending()
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "last", pending[0].ID)
+}
@@ -1,10 +1,11 @@
 package pocketbase
 
+// maxPageSize is the maximum perPage value accepted by PocketBase.
+const maxPageSize = 500
+
 type ParamsList struct {
 	Page    int
 	Size    int
 	Filters string
 	Sort    string
-
-	hackResponseRef any //hack for collection list
 }
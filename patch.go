@@ -0,0 +1,218 @@
+package pocketbase
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var ErrPatchCollision = errors.New("patch modifiers collide")
+
+// Patch builds a partial update using PocketBase field modifiers:
+// "field+" appends to select/relation fields or increments numbers, "field-" removes or decrements.
+// A field can't be both appended to and incremented, or removed from and decremented:
+// both map to the same modifier and marshaling fails with ErrPatchCollision.
+// The zero value is an empty patch.
+type Patch struct {
+	values  map[string]any
+	appends map[string][]string
+	removes map[string][]string
+	deltas  map[string]float64
+}
+
+func NewPatch() *Patch {
+	return &Patch{}
+}
+
+// NewPatchDiff returns a patch with the JSON fields that differ between before and after.
+// Fields dropped from after by omitempty are unset, so zero values can clear data.
+// Values are compared as encoded by encoding/json, Collection.PatchDiff uses the client JSON codec.
+func NewPatchDiff[T any](before, after T) (*Patch, error) {
+	return newPatchDiff(stdJSONCodec{}, before, after)
+}
+
+func newPatchDiff[T any](codec JSONCodec, before, after T) (*Patch, error) {
+	oldFields, err := jsonFields(codec, before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := jsonFields(codec, after)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewPatch()
+	for field, value := range newFields {
+		if !bytes.Equal(oldFields[field], value) {
+			p.Set(field, value)
+		}
+	}
+	for field := range oldFields {
+		if _, ok := newFields[field]; !ok {
+			p.Unset(field)
+		}
+	}
+	return p, nil
+}
+
+// NewPatchMask returns a patch with only the given JSON fields of value.
+// Masked fields dropped by omitempty are unset.
+func NewPatchMask[T any](value T, fields ...string) (*Patch, error) {
+	return newPatchMask(stdJSONCodec{}, value, fields...)
+}
+
+func newPatchMask[T any](codec JSONCodec, value T, fields ...string) (*Patch, error) {
+	values, err := jsonFields(codec, value)
+	if err != nil {
+		return nil, err
+	}
+
+	p := NewPatch()
+	for _, field := range fields {
+		if value, ok := values[field]; ok {
+			p.Set(field, value)
+		} else {
+			p.Unset(field)
+		}
+	}
+	return p, nil
+}
+
+func jsonFields(codec JSONCodec, value any) (map[string]json.RawMessage, error) {
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("[patch] can't marshal value, err %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := codec.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("[patch] value must marshal to a JSON object, err %w", err)
+	}
+	return fields, nil
+}
+
+// Set replaces the field value.
+func (p *Patch) Set(field string, value any) *Patch {
+	if p.values == nil {
+		p.values = map[string]any{}
+	}
+	p.values[field] = value
+	return p
+}
+
+// Unset clears the field.
+func (p *Patch) Unset(field string) *Patch {
+	return p.Set(field, nil)
+}
+
+// Append adds values to a multiple select or relation field.
+func (p *Patch) Append(field string, values ...string) *Patch {
+	if p.appends == nil {
+		p.appends = map[string][]string{}
+	}
+	p.appends[field] = append(p.appends[field], values...)
+	return p
+}
+
+// Remove removes values from a multiple select, relation or file field.
+func (p *Patch) Remove(field string, values ...string) *Patch {
+	if p.removes == nil {
+		p.removes = map[string][]string{}
+	}
+	p.removes[field] = append(p.removes[field], values...)
+	return p
+}
+
+// Increment adds delta to a number field, negative delta decrements it.
+func (p *Patch) Increment(field string, delta float64) *Patch {
+	if p.deltas == nil {
+		p.deltas = map[string]float64{}
+	}
+	p.deltas[field] += delta
+	return p
+}
+
+func (p *Patch) Decrement(field string, delta float64) *Patch {
+	return p.Increment(field, -delta)
+}
+
+// Fields returns the request body keys, sorted.
+func (p *Patch) Fields() []string {
+	body, _ := p.body()
+	fields := make([]string, 0, len(body))
+	for field := range body {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func (p *Patch) IsEmpty() bool {
+	body, _ := p.body()
+	return len(body) == 0
+}
+
+func (p *Patch) MarshalJSON() ([]byte, error) {
+	body, err := p.body()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(body)
+}
+
+func (p *Patch) body() (map[string]any, error) {
+	body := make(map[string]any, len(p.values)+len(p.appends)+len(p.removes)+len(p.deltas))
+	var err error
+	set := func(key string, value any) {
+		if _, ok := body[key]; ok && err == nil {
+			err = fmt.Errorf("[patch] %s is set twice, err %w", key, ErrPatchCollision)
+		}
+		body[key] = value
+	}
+
+	for field, value := range p.values {
+		set(field, value)
+	}
+	for field, values := range p.appends {
+		set(field+"+", values)
+	}
+	for field, values := range p.removes {
+		set(field+"-", values)
+	}
+	for field, delta := range p.deltas {
+		switch {
+		case delta > 0:
+			set(field+"+", delta)
+		case delta < 0:
+			set(field+"-", -delta)
+		}
+	}
+	return body, err
+}
+
+// Patch sends only the fields and modifiers of p.
+func (c Collection[T]) Patch(id string, p *Patch) error {
+	return c.Client.Update(c.Name, id, p)
+}
+
+// PatchDiff sends only the fields changed between before and after, nothing if they are equal.
+func (c Collection[T]) PatchDiff(id string, before, after T) error {
+	p, err := newPatchDiff(c.codec, before, after)
+	if err != nil {
+		return err
+	}
+	if p.IsEmpty() {
+		return nil
+	}
+	return c.Patch(id, p)
+}
+
+// PatchMask sends only the given JSON fields of value.
+func (c Collection[T]) PatchMask(id string, value T, fields ...string) error {
+	p, err := newPatchMask(c.codec, value, fields...)
+	if err != nil {
+		return err
+	}
+	return c.Patch(id, p)
+}
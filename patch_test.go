@@ -0,0 +1,157 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatch_MarshalJSON(t *testing.T) {
+	p := NewPatch().
+		Set("field", "value").
+		Unset("other").
+		Append("tags", "a").
+		Append("tags", "b").
+		Remove("relation", "id1").
+		Increment("count", 2).
+		Decrement("count", 5)
+
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"field": "value",
+		"other": null,
+		"tags+": ["a", "b"],
+		"relation-": ["id1"],
+		"count-": 3
+	}`, string(data))
+	assert.Equal(t, []string{"count-", "field", "other", "relation-", "tags+"}, p.Fields())
+	assert.True(t, (&Patch{}).IsEmpty())
+}
+
+func TestPatch_Collision(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch *Patch
+	}{
+		{
+			name:  "Append and increment",
+			patch: NewPatch().Append("tags", "a").Increment("tags", 1),
+		},
+		{
+			name:  "Remove and decrement",
+			patch: NewPatch().Remove("tags", "a").Decrement("tags", 1),
+		},
+		{
+			name:  "Modifier set as a value",
+			patch: NewPatch().Set("tags+", []string{"a"}).Append("tags", "b"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := json.Marshal(tt.patch)
+			assert.ErrorIs(t, err, ErrPatchCollision)
+		})
+	}
+}
+
+func TestCollection_Patch(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := "value_" + time.Now().Format(time.StampMilli)
+	collection := Collection[map[string]any]{client, migrations.PostsPublic}
+
+	created, err := collection.Create(map[string]any{
+		"field":               field,
+		migrations.FieldCount: 10,
+		migrations.FieldTags:  []string{"a"},
+	})
+	require.NoError(t, err)
+
+	err = collection.Patch(created.ID, NewPatch().
+		Increment(migrations.FieldCount, 5).
+		Append(migrations.FieldTags, "b", "c").
+		Remove(migrations.FieldTags, "a"))
+	require.NoError(t, err)
+
+	item, err := collection.One(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, field, item["field"])
+	assert.EqualValues(t, 15, item[migrations.FieldCount])
+	assert.ElementsMatch(t, []any{"b", "c"}, item[migrations.FieldTags])
+
+	err = collection.Patch(created.ID, NewPatch().Decrement(migrations.FieldCount, 20).Unset(migrations.FieldTags))
+	require.NoError(t, err)
+
+	item, err = collection.One(created.ID)
+	require.NoError(t, err)
+	assert.EqualValues(t, -5, item[migrations.FieldCount])
+	assert.Empty(t, item[migrations.FieldTags])
+}
+
+func TestNewPatchDiff(t *testing.T) {
+	type post struct {
+		ID    string   `json:"id"`
+		Field string   `json:"field,omitempty"`
+		Count int      `json:"count"`
+		Tags  []string `json:"tags"`
+	}
+
+	old := post{ID: "id", Field: "value", Count: 1, Tags: []string{"a"}}
+
+	p, err := NewPatchDiff(old, old)
+	require.NoError(t, err)
+	assert.True(t, p.IsEmpty())
+
+	p, err = NewPatchDiff(old, post{ID: "id", Count: 2, Tags: []string{"a"}})
+	require.NoError(t, err)
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"field": null, "count": 2}`, string(data))
+
+	p, err = NewPatchMask(post{ID: "id", Count: 3}, "field", "count")
+	require.NoError(t, err)
+	data, err = json.Marshal(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"field": null, "count": 3}`, string(data))
+}
+
+func TestCollection_PatchDiff(t *testing.T) {
+	type post struct {
+		ID    string   `json:"id,omitempty"`
+		Field string   `json:"field,omitempty"`
+		Count int      `json:"count,omitempty"`
+		Tags  []string `json:"tags,omitempty"`
+	}
+
+	client := NewClient(defaultURL)
+	field := "value_" + time.Now().Format(time.StampMilli)
+	collection := CollectionSet[post](client, migrations.PostsPublic)
+
+	created, err := collection.Create(post{Field: field, Count: 7, Tags: []string{"a"}})
+	require.NoError(t, err)
+	old, err := collection.One(created.ID)
+	require.NoError(t, err)
+
+	// concurrent change of another field must survive the diff update
+	require.NoError(t, collection.Patch(created.ID, NewPatch().Set("field", field+"_other")))
+
+	updated := old
+	updated.Count = 0
+	require.NoError(t, collection.PatchDiff(created.ID, old, updated))
+
+	item, err := collection.One(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, field+"_other", item.Field)
+	assert.Equal(t, 0, item.Count)
+	assert.Equal(t, []string{"a"}, item.Tags)
+
+	require.NoError(t, collection.PatchMask(created.ID, post{Tags: []string{"b"}}, "tags"))
+	item, err = collection.One(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, item.Tags)
+	assert.Equal(t, field+"_other", item.Field)
+}
@@ -0,0 +1,103 @@
+package pocketbase
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// WithCoalescing makes concurrent identical One and List calls share a single request and its result.
+// The shared request keeps the values of the first caller's context but not its cancellation,
+// each caller stops waiting when its own context is done.
+func WithCoalescing() ClientOption {
+	return func(c *Client) {
+		c.reads = &singleflight.Group{}
+	}
+}
+
+// fetched is a response with the cache generation of its collection before it was requested.
+type fetched struct {
+	body       []byte
+	generation uint64
+}
+
+// coalesce runs fetch once for concurrent calls with the same key when coalescing is enabled.
+func (c *Client) coalesce(collection string, key string, fetch func(c *Client) ([]byte, error)) (fetched, error) {
+	shared := func(c *Client) (fetched, error) {
+		generation := c.cache.generation(collection)
+		body, err := fetch(c)
+		return fetched{body: body, generation: generation}, err
+	}
+	if c.reads == nil {
+		return shared(c)
+	}
+
+	results := c.reads.DoChan(key, func() (any, error) {
+		return shared(c.WithContext(context.WithoutCancel(c.ctx)))
+	})
+	select {
+	case <-c.ctx.Done():
+		return fetched{}, c.ctx.Err()
+	case result := <-results:
+		if result.Err != nil {
+			return fetched{}, result.Err
+		}
+		return result.Val.(fetched), nil
+	}
+}
+
+// readOne is one served from the cache and coalesced when they're enabled.
+func (c *Client) readOne(collection string, id string) ([]byte, error) {
+	key := oneKey(collection, id)
+	cached := c.cache.caches(collection)
+	if cached {
+		if body, ok := c.cache.Cache.Get(key); ok {
+			return body, nil
+		}
+	}
+
+	result, err := c.coalesce(collection, key, func(c *Client) ([]byte, error) {
+		return c.one(collection, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached {
+		c.cache.set(collection, result.generation, key, result.body)
+	}
+	return result.body, nil
+}
+
+// readList is list served from the cache and coalesced when they're enabled,
+// otherwise the response is streamed.
+func (c *Client) readList(collection string, params ParamsList) (io.ReadCloser, error) {
+	cached := c.cache.caches(collection)
+	if !cached && c.reads == nil {
+		return c.list(collection, params)
+	}
+
+	key := listKey(collection, params)
+	if cached {
+		if body, ok := c.cache.Cache.Get(key); ok {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	result, err := c.coalesce(collection, key, func(c *Client) ([]byte, error) {
+		rc, err := c.list(collection, params)
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cached {
+		c.cache.set(collection, result.generation, key, result.body)
+	}
+	return io.NopCloser(bytes.NewReader(result.body)), nil
+}
@@ -0,0 +1,108 @@
+package pocketbase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCoalescing(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []ClientOption
+		ids          []string
+		wantRequests int32
+	}{
+		{
+			name:         "Identical reads share a request",
+			opts:         []ClientOption{WithCoalescing()},
+			ids:          []string{"a", "a", "a", "a", "a", "a"},
+			wantRequests: 2, // one and list
+		},
+		{
+			name:         "Different reads are not shared",
+			opts:         []ClientOption{WithCoalescing()},
+			ids:          []string{"a", "b", "a", "b"},
+			wantRequests: 4,
+		},
+		{
+			name:         "Disabled by default",
+			ids:          []string{"a", "a", "a"},
+			wantRequests: 6,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				time.Sleep(100 * time.Millisecond)
+				if r.URL.Path == "/api/collections/posts/records" {
+					_, _ = w.Write([]byte(`{"page": 1, "items": [{"id": "` + r.URL.Query().Get("filter") + `"}]}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"id": "` + r.URL.Path[len("/api/collections/posts/records/"):] + `"}`))
+			}))
+			defer server.Close()
+
+			posts := CollectionSet[map[string]any](NewClient(server.URL, tt.opts...), "posts")
+			var wg sync.WaitGroup
+			for _, id := range tt.ids {
+				wg.Add(2)
+				go func(id string) {
+					defer wg.Done()
+					record, err := posts.One(id)
+					assert.NoError(t, err)
+					assert.Equal(t, id, record["id"])
+				}(id)
+				go func(id string) {
+					defer wg.Done()
+					list, err := posts.List(ParamsList{Filters: id})
+					assert.NoError(t, err)
+					assert.Equal(t, id, list.Items[0]["id"])
+				}(id)
+			}
+			wg.Wait()
+			assert.Equal(t, tt.wantRequests, requests.Load())
+		})
+	}
+}
+
+func TestWithCoalescing_Cancel(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"id": "a"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithCoalescing())
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error)
+	go func() {
+		_, err := CollectionSet[map[string]any](client.WithContext(ctx), "posts").One("a")
+		canceled <- err
+	}()
+	require.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, time.Millisecond)
+
+	shared := make(chan error)
+	go func() {
+		record, err := CollectionSet[map[string]any](client, "posts").One("a")
+		assert.Equal(t, "a", record["id"])
+		shared <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+	assert.NoError(t, <-shared)
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,79 @@
+package pocketbase
+
+import (
+	"time"
+
+	"github.com/duke-git/lancet/v2/convertor"
+	"github.com/r--w/pocketbase/types"
+)
+
+// Record is an untyped record as returned by Client.List.
+// The getters return zero values for missing fields or fields of another type.
+type Record map[string]any
+
+func (r Record) Get(field string) any {
+	return r[field]
+}
+
+func (r Record) ID() string {
+	return r.GetString("id")
+}
+
+// GetString returns string fields as is and formats numbers and bools.
+func (r Record) GetString(field string) string {
+	switch v := r[field].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64, bool:
+		return convertor.ToString(v)
+	}
+	return ""
+}
+
+func (r Record) GetBool(field string) bool {
+	v, _ := r[field].(bool)
+	return v
+}
+
+func (r Record) GetFloat(field string) float64 {
+	v, _ := r[field].(float64)
+	return v
+}
+
+func (r Record) GetInt(field string) int {
+	return int(r.GetFloat(field))
+}
+
+// GetDateTime parses date fields and the created/updated timestamps.
+func (r Record) GetDateTime(field string) types.DateTime {
+	d, _ := types.ParseDateTime(r.GetString(field))
+	return d
+}
+
+func (r Record) GetTime(field string) time.Time {
+	return r.GetDateTime(field).Time()
+}
+
+// GetStrings returns multiple select, relation and file fields, a single string value is wrapped in a slice.
+func (r Record) GetStrings(field string) []string {
+	switch v := r[field].(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
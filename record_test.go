@@ -0,0 +1,66 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord_Getters(t *testing.T) {
+	var r Record
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "abc",
+		"field": "value",
+		"count": 3,
+		"active": true,
+		"created": "2022-11-12 17:55:13.123Z",
+		"tags": ["a", "b"],
+		"single": "a",
+		"empty": ""
+	}`), &r))
+
+	assert.Equal(t, "abc", r.ID())
+	assert.Equal(t, "value", r.GetString("field"))
+	assert.Equal(t, "3", r.GetString("count"))
+	assert.Equal(t, "", r.GetString("missing"))
+	assert.Equal(t, 3, r.GetInt("count"))
+	assert.Equal(t, 3.0, r.GetFloat("count"))
+	assert.True(t, r.GetBool("active"))
+	assert.False(t, r.GetBool("field"))
+	assert.Equal(t, time.Date(2022, 11, 12, 17, 55, 13, 123e6, time.UTC), r.GetTime("created"))
+	assert.True(t, r.GetTime("field").IsZero())
+	assert.Equal(t, []string{"a", "b"}, r.GetStrings("tags"))
+	assert.Equal(t, []string{"a"}, r.GetStrings("single"))
+	assert.Nil(t, r.GetStrings("empty"))
+	assert.Nil(t, r.GetStrings("missing"))
+}
+
+func TestWithDecoder(t *testing.T) {
+	var decoded []string
+	decoder := DecoderFunc(func(data []byte, v any) error {
+		decoded = append(decoded, fmt.Sprintf("%T", v))
+		return json.Unmarshal(data, v)
+	})
+	client := NewClient(defaultURL, WithDecoder(decoder))
+
+	created, err := client.Create(migrations.PostsPublic, map[string]any{"field": "decoder_" + time.Now().Format(time.StampMilli)})
+	require.NoError(t, err)
+
+	list, err := client.List(migrations.PostsPublic, ParamsList{Filters: "id='" + created.ID + "'"})
+	require.NoError(t, err)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, created.ID, list.Items[0].ID())
+
+	posts := CollectionSet[map[string]any](client, migrations.PostsPublic)
+	_, err = posts.One(created.ID)
+	assert.NoError(t, err)
+	// records only, never the list envelope
+	assert.Equal(t, []string{"*pocketbase.Record", "*map[string]interface {}"}, decoded)
+
+	assert.NoError(t, client.Delete(migrations.PostsPublic, created.ID))
+}
@@ -1,5 +1,7 @@
 package pocketbase
 
+import "github.com/r--w/pocketbase/types"
+
 type ResponseList[T any] struct {
 	Page       int `json:"page"`
 	PerPage    int `json:"perPage"`
@@ -9,8 +11,8 @@ type ResponseList[T any] struct {
 }
 
 type ResponseCreate struct {
-	ID      string `json:"id"`
-	Created string `json:"created"`
-	Field   string `json:"field"`
-	Updated string `json:"updated"`
+	ID      string         `json:"id"`
+	Created types.DateTime `json:"created"`
+	Field   string         `json:"field"`
+	Updated types.DateTime `json:"updated"`
 }
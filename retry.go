@@ -0,0 +1,214 @@
+package pocketbase
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+// RetryPolicy controls which failed requests are resent.
+// Requests with other methods than Methods, POST and PATCH by default, are resent only
+// when the server can't have processed them: the connection failed or it answered 429 Too Many Requests.
+type RetryPolicy struct {
+	// MaxRetries is the number of resends after the first attempt, 0 disables retrying.
+	MaxRetries int
+	// Methods are retried on transport errors and StatusCodes.
+	Methods     []string
+	StatusCodes []int
+	// NewBackOff returns the wait strategy for a single request.
+	NewBackOff func() backoff.BackOff
+	// MaxRetryAfter caps the wait requested by the Retry-After header of 429 and 503 responses,
+	// longer waits are not retried.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy retries safe and idempotent requests 3 times, waiting 3s growing up to 10s, with ±50% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		Methods: []string{
+			http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete,
+		},
+		StatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		NewBackOff: func() backoff.BackOff {
+			b := backoff.NewExponentialBackOff()
+			b.InitialInterval = 3 * time.Second
+			b.MaxInterval = 10 * time.Second
+			b.MaxElapsedTime = 0
+			// the constructor already started at the library default interval
+			b.Reset()
+			return b
+		},
+		MaxRetryAfter: time.Minute,
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy, empty fields of policy other than MaxRetries keep their default values.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		defaults := DefaultRetryPolicy()
+		if policy.Methods == nil {
+			policy.Methods = defaults.Methods
+		}
+		if policy.StatusCodes == nil {
+			policy.StatusCodes = defaults.StatusCodes
+		}
+		if policy.NewBackOff == nil {
+			policy.NewBackOff = defaults.NewBackOff
+		}
+		if policy.MaxRetryAfter == 0 {
+			policy.MaxRetryAfter = defaults.MaxRetryAfter
+		}
+		c.retry = policy
+	}
+}
+
+func (p RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return req.Context().Err() == nil && p.retriesMethod(req.Method)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if !p.retriesMethod(req.Method) {
+		return false
+	}
+	for _, code := range p.StatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) retriesMethod(method string) bool {
+	for _, m := range p.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// retryTransport resends requests according to the RetryPolicy.
+type retryTransport struct {
+	base      http.RoundTripper
+	policy    RetryPolicy
+	telemetry *telemetry
+	logger    *slog.Logger
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.policy.MaxRetries <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	b := backoff.WithContext(backoff.WithMaxRetries(t.policy.NewBackOff(), uint64(t.policy.MaxRetries)), req.Context())
+	attempt := req
+	for retries := 1; ; retries++ {
+		resp, err := t.base.RoundTrip(attempt)
+		if !t.policy.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := b.NextBackOff()
+		if wait == backoff.Stop {
+			return resp, err
+		}
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				if after > t.policy.MaxRetryAfter {
+					return resp, err
+				}
+				wait = after
+			}
+		}
+
+		next, ok := rewind(req)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		t.telemetry.retried(req, retries)
+		t.logRetry(req, resp, err, retries, wait)
+		attempt = next
+	}
+}
+
+func (t *retryTransport) logRetry(req *http.Request, resp *http.Response, err error, attempt int, wait time.Duration) {
+	if t.logger == nil {
+		return
+	}
+	op, _ := OperationFromContext(req.Context())
+	attrs := []any{"operation", op.Name, "method", req.Method, "path", req.URL.Path, "attempt", attempt, "wait", wait}
+	if err != nil {
+		attrs = append(attrs, "err", err)
+	} else {
+		attrs = append(attrs, "status", resp.StatusCode)
+	}
+	t.logger.Info("retrying pocketbase request", attrs...)
+}
+
+// rewind returns a copy of req with a fresh body, false if the body can't be replayed.
+func rewind(req *http.Request) (*http.Request, bool) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	next.Body = body
+	return next, true
+}
+
+// retryAfter parses the Retry-After header of 429 and 503 responses, in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
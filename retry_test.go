@@ -0,0 +1,182 @@
+package pocketbase
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    []int
+		header    http.Header
+		request   func(c *Client) error
+		wantCalls int32
+		wantErr   bool
+	}{
+		{
+			name:      "GET retried on 503",
+			status:    []int{503, 503, 200},
+			request:   func(c *Client) error { _, err := c.List("posts", ParamsList{}); return err },
+			wantCalls: 3,
+		},
+		{
+			name:      "GET gives up after max retries",
+			status:    []int{502, 502, 502, 502, 200},
+			request:   func(c *Client) error { _, err := c.List("posts", ParamsList{}); return err },
+			wantCalls: 3,
+			wantErr:   true,
+		},
+		{
+			name:      "GET not retried on 400",
+			status:    []int{400, 200},
+			request:   func(c *Client) error { _, err := c.List("posts", ParamsList{}); return err },
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "POST not retried on 503",
+			status:    []int{503, 200},
+			request:   func(c *Client) error { _, err := c.Create("posts", map[string]any{"field": "value"}); return err },
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "POST retried on 429 with its body",
+			status:    []int{429, 200},
+			header:    http.Header{"Retry-After": []string{"0"}},
+			request:   func(c *Client) error { _, err := c.Create("posts", map[string]any{"field": "value"}); return err },
+			wantCalls: 2,
+		},
+		{
+			name:      "Retry-After above the limit is not waited for",
+			status:    []int{429, 200},
+			header:    http.Header{"Retry-After": []string{"3600"}},
+			request:   func(c *Client) error { _, err := c.List("posts", ParamsList{}); return err },
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := calls.Add(1)
+				if r.Method == http.MethodPost {
+					body, _ := io.ReadAll(r.Body)
+					assert.JSONEq(t, `{"field": "value"}`, string(body))
+				}
+				status := tt.status[call-1]
+				if status != http.StatusOK {
+					for key, values := range tt.header {
+						w.Header()[key] = values
+					}
+				}
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"id": "abc"}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(server.URL, WithRetryPolicy(RetryPolicy{
+				MaxRetries: 2,
+				NewBackOff: func() backoff.BackOff { return &backoff.ZeroBackOff{} },
+			}))
+			err := tt.request(client)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
+
+func TestRetryTransport_ConnectionErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		err       error
+		wantCalls int32
+	}{
+		{
+			name:      "POST retried when dial fails",
+			method:    http.MethodPost,
+			err:       &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			wantCalls: 3,
+		},
+		{
+			name:      "POST not retried when connection breaks",
+			method:    http.MethodPost,
+			err:       &net.OpError{Op: "read", Err: errors.New("connection reset")},
+			wantCalls: 1,
+		},
+		{
+			name:      "GET retried when connection breaks",
+			method:    http.MethodGet,
+			err:       &net.OpError{Op: "read", Err: errors.New("connection reset")},
+			wantCalls: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			policy := DefaultRetryPolicy()
+			policy.MaxRetries = 2
+			policy.NewBackOff = func() backoff.BackOff { return &backoff.ZeroBackOff{} }
+			transport := &retryTransport{
+				base: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					calls.Add(1)
+					return nil, tt.err
+				}),
+				policy: policy,
+			}
+
+			req, err := http.NewRequest(tt.method, "http://127.0.0.1/api", strings.NewReader("{}"))
+			require.NoError(t, err)
+			_, err = transport.RoundTrip(req)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
+
+func TestDefaultRetryPolicy_BackOff(t *testing.T) {
+	b := DefaultRetryPolicy().NewBackOff()
+
+	first := b.NextBackOff()
+	assert.GreaterOrEqual(t, first, 1500*time.Millisecond)
+	assert.LessOrEqual(t, first, 4500*time.Millisecond)
+	for i := 0; i < 10; i++ {
+		wait := b.NextBackOff()
+		assert.GreaterOrEqual(t, wait, 1500*time.Millisecond)
+		assert.LessOrEqual(t, wait, 15*time.Second)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	_, ok := retryAfter(resp)
+	assert.False(t, ok)
+
+	resp.Header.Set("Retry-After", "2")
+	wait, ok := retryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, wait)
+
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	wait, ok = retryAfter(resp)
+	assert.True(t, ok)
+	assert.InDelta(t, time.Hour, wait, float64(5*time.Second))
+
+	resp.StatusCode = http.StatusBadGateway
+	_, ok = retryAfter(resp)
+	assert.False(t, ok)
+}
@@ -4,19 +4,61 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/SierraSoftworks/multicast/v2"
 	"github.com/cenkalti/backoff/v4"
 	"github.com/donovanhide/eventsource"
 )
 
+type EventAction string
+
+const (
+	ActionCreate EventAction = "create"
+	ActionUpdate EventAction = "update"
+	ActionDelete EventAction = "delete"
+)
+
 type Event[T any] struct {
-	Action string `json:"action"`
-	Record T      `json:"record"`
-	Error  error  `json:"-"`
+	Action EventAction     `json:"action"`
+	Record T               `json:"record"`
+	Raw    json.RawMessage `json:"-"`
+	Error  error           `json:"-"`
+
+	id string
+}
+
+func (e Event[T]) IsCreate() bool {
+	return e.Action == ActionCreate
+}
+
+func (e Event[T]) IsUpdate() bool {
+	return e.Action == ActionUpdate
+}
+
+// IsDelete reports whether the event is a delete; Record then holds the last state of the deleted record.
+func (e Event[T]) IsDelete() bool {
+	return e.Action == ActionDelete
+}
+
+// decodeEvent decodes the event envelope with the codec and its record with the client Decoder.
+func decodeEvent[T any](c *Client, e *Event[T]) error {
+	var envelope struct {
+		Action EventAction     `json:"action"`
+		Record json.RawMessage `json:"record"`
+	}
+	if err := c.codec.Unmarshal(e.Raw, &envelope); err != nil {
+		return err
+	}
+	e.Action = envelope.Action
+
+	record, err := decodeIDRecord[T](c, envelope.Record)
+	e.id, e.Record = record.ID, record.Record
+	return err
 }
 
 func (c Collection[T]) Subscribe(targets ...string) (*Stream[T], error) {
@@ -27,40 +69,99 @@ func (c Collection[T]) Subscribe(targets ...string) (*Stream[T], error) {
 }
 
 type SubscribeOptions struct {
+	// ReconnectStrategy paces reconnects of a dropped connection, immediately if nil.
 	ReconnectStrategy backoff.BackOff
+	// SeparateErrors delivers decode failures on Stream.Errors() instead of Event.Error.
+	// Events are delivered in order, so Errors() must be drained as well.
+	SeparateErrors bool
+	// HandlerWorkers is the number of goroutines running OnCreate/OnUpdate/OnDelete handlers.
+	// Events of the same record are always handled by the same worker, in order.
+	HandlerWorkers int
+	// OnReconnect is called after a dropped connection was re-established and
+	// subscriptions were re-submitted, before any new event is delivered.
+	OnReconnect func()
 }
 
 func (c Collection[T]) SubscribeWith(opts SubscribeOptions, targets ...string) (*Stream[T], error) {
-	if err := c.Authorize(); err != nil {
+	if len(targets) == 0 {
+		targets = []string{c.Name}
+	}
+
+	stream := newStream[T](c.logger)
+	stream.handlers.workers = opts.HandlerWorkers
+
+	handleSSEEvent := func(ev eventsource.Event) {
+		e := Event[T]{Raw: json.RawMessage(ev.Data())}
+		if err := decodeEvent(c.Client, &e); err != nil {
+			if opts.SeparateErrors {
+				stream.publishError(fmt.Errorf("[realtime] can't unmarshal event, err %w", err))
+				return
+			}
+			e.Error = err
+		}
+		stream.publish(e)
+	}
+
+	if err := startRealtime(c.Client, stream.realtimeStream, opts, targets, handleSSEEvent); err != nil {
 		return nil, err
 	}
+	return stream, nil
+}
 
-	if len(targets) == 0 {
-		targets = []string{c.Name}
+// startRealtime connects s to the realtime API and keeps the connection alive with
+// opts.ReconnectStrategy until s is unsubscribed. handle is called for every event, in order.
+func startRealtime[E any](c *Client, s *realtimeStream[E], opts SubscribeOptions, targets []string, handle func(eventsource.Event)) error {
+	if err := c.Authorize(); err != nil {
+		return err
+	}
+	if opts.ReconnectStrategy == nil {
+		opts.ReconnectStrategy = &backoff.ZeroBackOff{}
 	}
 
-	stream := newStream[T]()
 	ctx, cancel := context.WithCancel(context.Background())
-	stream.unsubscribe = func() { cancel() }
+	s.unsubscribe = func() { cancel() }
+	s.done = ctx.Done()
 
-	handleSSEEvent := func(ev eventsource.Event) {
-		var e Event[T]
-		e.Error = json.Unmarshal([]byte(ev.Data()), &e)
-		stream.channel.C <- e
+	// clientID of the live realtime connection, used to re-submit subscriptions on token refresh
+	var (
+		clientMu sync.Mutex
+		clientID string
+	)
+	setClientID := func(id string) {
+		clientMu.Lock()
+		defer clientMu.Unlock()
+		clientID = id
 	}
 
 	once := &sync.Once{}
-	stream.ready.Lock()
+	connected := false
+	s.ready.Lock()
 	startStream := func(check bool) func() error {
 		return func() (err error) {
-			req := c.client.R().SetContext(ctx).SetDoNotParseResponse(true)
+			setClientID("")
+			if err := c.authorizer.authorize(ctx); err != nil {
+				return err
+			}
+
+			req := c.client.R().
+				SetContext(withOperation(withStreaming(ctx), Operation{Name: OperationRealtime})).
+				SetDoNotParseResponse(true)
 			resp, err := req.Get(c.url + "/api/realtime")
-			defer resp.RawBody().Close()
 			if err != nil {
-				return
+				return fmt.Errorf("[realtime] can't connect to pocketbase, err %w", err)
+			}
+			body := resp.RawBody()
+			defer body.Close()
+			if resp.IsError() {
+				msg, _ := io.ReadAll(body)
+				return fmt.Errorf("[realtime] pocketbase returned status: %d, msg: %s, err %w",
+					resp.StatusCode(),
+					msg,
+					ErrInvalidResponse,
+				)
 			}
 
-			d := eventsource.NewDecoder(resp.RawBody())
+			d := eventsource.NewDecoder(body)
 
 			ev, err := d.Decode()
 			if err != nil {
@@ -70,20 +171,33 @@ func (c Collection[T]) SubscribeWith(opts SubscribeOptions, targets ...string) (
 				return fmt.Errorf("first event must be PB_CONNECT, but got %s", event)
 			}
 
-			if err := c.authSubscribeStream([]byte(ev.Data()), targets); err != nil {
+			var set SubscriptionsSet
+			if err := c.codec.Unmarshal([]byte(ev.Data()), &set); err != nil {
+				return err
+			}
+			if err := c.authSubscribeStream(set.ClientID, targets); err != nil {
 				return err
 			}
 
 			if !check {
+				setClientID(set.ClientID)
+				if connected {
+					c.telemetry.reconnected()
+					if opts.OnReconnect != nil {
+						opts.OnReconnect()
+					}
+				}
+				connected = true
 				once.Do(func() {
-					stream.ready.Unlock()
+					s.ready.Unlock()
 				})
 				for {
 					ev, err := d.Decode()
 					if err != nil {
 						return err
 					}
-					go handleSSEEvent(ev)
+					// handled in place to keep the server's event order
+					handle(ev)
 				}
 			}
 
@@ -92,16 +206,39 @@ func (c Collection[T]) SubscribeWith(opts SubscribeOptions, targets ...string) (
 	}
 
 	if err := startStream(true)(); err != nil {
-		return nil, err
+		cancel()
+		return err
+	}
+
+	removeAuthListener := c.AuthStore().onChange(func(string) {
+		clientMu.Lock()
+		id := clientID
+		clientMu.Unlock()
+		if id == "" || ctx.Err() != nil {
+			return
+		}
+		if err := c.authSubscribeStream(id, targets); err != nil {
+			c.logError(ctx, "realtime resubscribe after token refresh failed", err)
+		}
+	})
+	s.unsubscribe = func() {
+		removeAuthListener()
+		cancel()
 	}
 
 	go func() {
-		if err := backoff.Retry(startStream(false), backoff.WithContext(opts.ReconnectStrategy, ctx)); err != nil {
-			log.Print(err)
+		reconnecting := func(err error, wait time.Duration) {
+			if ctx.Err() == nil {
+				c.logger.Warn("realtime connection lost, reconnecting", "err", err, "wait", wait)
+			}
+		}
+		err := backoff.RetryNotify(startStream(false), backoff.WithContext(opts.ReconnectStrategy, ctx), reconnecting)
+		if err != nil {
+			c.logError(ctx, "realtime connection closed", err)
 		}
 	}()
 
-	return stream, nil
+	return nil
 }
 
 type SubscriptionsSet struct {
@@ -109,13 +246,17 @@ type SubscriptionsSet struct {
 	Subscriptions []string `json:"subscriptions"`
 }
 
-func (c Collection[T]) authSubscribeStream(data []byte, targets []string) (err error) {
-	var s SubscriptionsSet
-	if err = json.Unmarshal(data, &s); err != nil {
-		return
+// authSubscribeStream submits the subscription set with the current auth token,
+// so rule-protected collections keep delivering events after the token rotates.
+func (c *Client) authSubscribeStream(clientID string, targets []string) (err error) {
+	s := SubscriptionsSet{
+		ClientID:      clientID,
+		Subscriptions: targets,
 	}
-	s.Subscriptions = targets
-	resp, err := c.client.R().SetBody(s).Post(c.url + "/api/realtime")
+	resp, err := c.request(Operation{Name: OperationSubscribe}).
+		SetHeader("Authorization", c.AuthStore().Token()).
+		SetBody(s).
+		Post(c.url + "/api/realtime")
 	if err != nil {
 		return
 	}
@@ -126,40 +267,101 @@ func (c Collection[T]) authSubscribeStream(data []byte, targets []string) (err e
 }
 
 type Stream[T any] struct {
-	channel     *multicast.Channel[Event[T]]
+	*realtimeStream[Event[T]]
+	handlers *streamHandlers[T]
+}
+
+func newStream[T any](logger *slog.Logger) *Stream[T] {
+	return &Stream[T]{
+		realtimeStream: newRealtimeStream[Event[T]](),
+		handlers:       &streamHandlers[T]{logger: logger},
+	}
+}
+
+func (s *Stream[T]) Events() <-chan Event[T] {
+	return s.channel.Listen().C
+}
+
+// realtimeStream is the delivery side shared by collection and topic streams.
+type realtimeStream[E any] struct {
+	channel     *multicast.Channel[E]
+	errors      *multicast.Channel[error]
 	unsubscribe func()
+	done        <-chan struct{}
 
 	ready       *sync.RWMutex
+	sending     *sync.RWMutex
 	onceCleanup *sync.Once
 }
 
-func newStream[T any]() *Stream[T] {
-	return &Stream[T]{
-		channel:     multicast.New[Event[T]](),
+func newRealtimeStream[E any]() *realtimeStream[E] {
+	return &realtimeStream[E]{
+		channel:     multicast.New[E](),
+		errors:      multicast.New[error](),
 		ready:       &sync.RWMutex{},
+		sending:     &sync.RWMutex{},
 		onceCleanup: &sync.Once{},
 	}
 }
 
-func (s *Stream[T]) Events() <-chan Event[T] {
-	return s.channel.Listen().C
+func (s *realtimeStream[E]) isDone() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// publish blocks until the event is consumed or the stream is unsubscribed.
+func (s *realtimeStream[E]) publish(e E) {
+	s.sending.RLock()
+	defer s.sending.RUnlock()
+	if s.isDone() {
+		return
+	}
+	select {
+	case <-s.done:
+	case s.channel.C <- e:
+	}
+}
+
+func (s *realtimeStream[E]) publishError(err error) {
+	s.sending.RLock()
+	defer s.sending.RUnlock()
+	if s.isDone() {
+		return
+	}
+	select {
+	case <-s.done:
+	case s.errors.C <- err:
+	}
+}
+
+// Errors receives decode failures when the stream was created with SubscribeOptions.SeparateErrors.
+func (s *realtimeStream[E]) Errors() <-chan error {
+	return s.errors.Listen().C
 }
 
-func (s *Stream[T]) Unsubscribe() {
+func (s *realtimeStream[E]) Unsubscribe() {
 	s.onceCleanup.Do(func() {
 		s.unsubscribe()
+		// wait for in-flight publishers, they are released by the cancelled context
+		s.sending.Lock()
+		defer s.sending.Unlock()
 		s.channel.Close()
+		s.errors.Close()
 	})
 }
 
 // Deprecated: use <-stream.Ready() instead of
-func (s *Stream[T]) WaitAuthReady() error {
+func (s *realtimeStream[E]) WaitAuthReady() error {
 	s.ready.RLock()
 	defer s.ready.RUnlock()
 	return nil
 }
 
-func (s *Stream[T]) Ready() <-chan struct{} {
+func (s *realtimeStream[E]) Ready() <-chan struct{} {
 	readyCh := make(chan struct{})
 	go func() {
 		s.ready.RLock()
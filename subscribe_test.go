@@ -3,6 +3,7 @@ package pocketbase
 import (
 	"net"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -33,8 +34,10 @@ func TestCollection_Subscribe(t *testing.T) {
 			return
 		}
 		e := <-ch
-		assert.Equal(t, "create", e.Action)
+		assert.Equal(t, ActionCreate, e.Action)
+		assert.True(t, e.IsCreate())
 		assert.Equal(t, resp.ID, e.Record["id"])
+		assert.Contains(t, string(e.Raw), resp.ID)
 	})
 
 	t.Run("subscribe event: update", func(t *testing.T) {
@@ -53,7 +56,8 @@ func TestCollection_Subscribe(t *testing.T) {
 			return
 		}
 		e := <-ch
-		assert.Equal(t, "update", e.Action)
+		assert.Equal(t, ActionUpdate, e.Action)
+		assert.True(t, e.IsUpdate())
 		assert.Equal(t, body["field"], e.Record["field"])
 	})
 
@@ -70,11 +74,104 @@ func TestCollection_Subscribe(t *testing.T) {
 			return
 		}
 		e := <-ch
-		assert.Equal(t, "delete", e.Action)
+		assert.Equal(t, ActionDelete, e.Action)
+		assert.True(t, e.IsDelete())
 		assert.Equal(t, resp.ID, e.Record["id"])
 	})
 }
 
+func TestCollection_SubscribeSeparateErrors(t *testing.T) {
+	client := NewClient(defaultURL)
+	defaultBody := map[string]interface{}{
+		"field": "value_" + time.Now().Format(time.StampMilli),
+	}
+	// field is a text column, so decoding it into int always fails
+	collection := Collection[struct {
+		Field int `json:"field"`
+	}]{client, migrations.PostsPublic}
+	// without a ReconnectStrategy the stream reconnects immediately
+	stream, err := collection.SubscribeWith(SubscribeOptions{SeparateErrors: true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer stream.Unsubscribe()
+	<-stream.Ready()
+
+	events := stream.Events()
+	errs := stream.Errors()
+
+	if _, err := client.Create(migrations.PostsPublic, defaultBody); err != nil {
+		t.Error(err)
+		return
+	}
+
+	select {
+	case err := <-errs:
+		assert.Error(t, err)
+	case e := <-events:
+		t.Errorf("decode failure delivered as event: %+v", e)
+	case <-time.After(5 * time.Second):
+		t.Error("decode failure was not delivered")
+	}
+}
+
+func TestCollection_SubscribeTokenRefresh(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		subscribed []string
+	)
+	client := NewClient(defaultURL,
+		WithUserEmailPassword(migrations.UserEmailPassword, migrations.UserEmailPassword),
+		WithAfterSend(func(op Operation, req *http.Request, resp *http.Response, err error) {
+			if op.Name == OperationSubscribe && err == nil && resp.StatusCode == http.StatusNoContent {
+				mu.Lock()
+				subscribed = append(subscribed, req.Header.Get("Authorization"))
+				mu.Unlock()
+			}
+		}))
+	defaultBody := map[string]interface{}{
+		"field": "value_" + time.Now().Format(time.StampMilli),
+	}
+	collection := Collection[map[string]any]{client, migrations.PostsUser}
+	stream, err := collection.Subscribe()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer stream.Unsubscribe()
+	<-stream.Ready()
+
+	ch := stream.Events()
+	mu.Lock()
+	initial := len(subscribed)
+	mu.Unlock()
+
+	// force token refresh, stream must re-submit its subscriptions with the new token
+	expireToken(client)
+	if err := client.Authorize(); err != nil {
+		t.Error(err)
+		return
+	}
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(subscribed) > initial && subscribed[len(subscribed)-1] == client.AuthStore().Token()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	resp, err := collection.Create(defaultBody)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	select {
+	case e := <-ch:
+		assert.Equal(t, resp.ID, e.Record["id"])
+	case <-time.After(5 * time.Second):
+		t.Error("no event after token refresh")
+	}
+}
+
 func TestCollection_Unsubscribe(t *testing.T) {
 	client := NewClient(defaultURL)
 	defaultBody := map[string]interface{}{
@@ -116,7 +213,6 @@ func TestCollection_RealtimeReconnect(t *testing.T) {
 		return
 	}
 
-	client := NewClient(defaultURL)
 	transport := &http.Transport{
 		Dial: func(network, addr string) (net.Conn, error) {
 			conn, err := net.Dial(network, addr)
@@ -127,7 +223,7 @@ func TestCollection_RealtimeReconnect(t *testing.T) {
 			return conn, err
 		},
 	}
-	client.client.SetTransport(transport)
+	client := NewClient(defaultURL, WithTransport(transport))
 	defaultBody := map[string]interface{}{
 		"field": "value_" + time.Now().Format(time.StampMilli),
 	}
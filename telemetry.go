@@ -0,0 +1,162 @@
+package pocketbase
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/r--w/pocketbase"
+
+// TelemetryOptions configures OpenTelemetry instrumentation, nil fields use the global providers.
+type TelemetryOptions struct {
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+	Propagator     propagation.TextMapPropagator
+}
+
+// WithTelemetry creates a client span for every call, named after its operation like "pocketbase.list",
+// propagates the trace context to PocketBase and records metrics:
+//
+//	pocketbase.client.requests            calls by operation, collection and status
+//	pocketbase.client.request.duration    call latency in seconds, retries included
+//	pocketbase.client.retries             resent requests
+//	pocketbase.client.auth.refreshes      authorization requests
+//	pocketbase.client.realtime.reconnects re-established realtime connections
+//
+// Use Client.WithContext to make the spans children of the caller's span.
+func WithTelemetry(opts TelemetryOptions) ClientOption {
+	return func(c *Client) {
+		c.telemetry = newTelemetry(opts)
+	}
+}
+
+type telemetry struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	requests   metric.Int64Counter
+	duration   metric.Float64Histogram
+	retries    metric.Int64Counter
+	refreshes  metric.Int64Counter
+	reconnects metric.Int64Counter
+}
+
+func newTelemetry(opts TelemetryOptions) *telemetry {
+	if opts.TracerProvider == nil {
+		opts.TracerProvider = otel.GetTracerProvider()
+	}
+	if opts.MeterProvider == nil {
+		opts.MeterProvider = otel.GetMeterProvider()
+	}
+	if opts.Propagator == nil {
+		opts.Propagator = otel.GetTextMapPropagator()
+	}
+
+	meter := opts.MeterProvider.Meter(instrumentationName)
+	t := &telemetry{
+		tracer:     opts.TracerProvider.Tracer(instrumentationName),
+		propagator: opts.Propagator,
+	}
+	// failed instruments are no-ops, the error goes to the global otel error handler
+	var err error
+	if t.requests, err = meter.Int64Counter("pocketbase.client.requests",
+		metric.WithDescription("PocketBase calls")); err != nil {
+		otel.Handle(err)
+	}
+	if t.duration, err = meter.Float64Histogram("pocketbase.client.request.duration",
+		metric.WithDescription("PocketBase call latency"), metric.WithUnit("s")); err != nil {
+		otel.Handle(err)
+	}
+	if t.retries, err = meter.Int64Counter("pocketbase.client.retries",
+		metric.WithDescription("Resent PocketBase requests")); err != nil {
+		otel.Handle(err)
+	}
+	if t.refreshes, err = meter.Int64Counter("pocketbase.client.auth.refreshes",
+		metric.WithDescription("PocketBase authorization requests")); err != nil {
+		otel.Handle(err)
+	}
+	if t.reconnects, err = meter.Int64Counter("pocketbase.client.realtime.reconnects",
+		metric.WithDescription("Re-established realtime connections")); err != nil {
+		otel.Handle(err)
+	}
+	return t
+}
+
+// middleware traces and measures calls, realtime spans end once the connection is established.
+func (t *telemetry) middleware(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		op, _ := OperationFromContext(req.Context())
+		name := op.Name
+		if name == "" {
+			name = "request"
+		}
+		attrs := []attribute.KeyValue{attribute.String("pocketbase.operation", name)}
+		if op.Collection != "" {
+			attrs = append(attrs, attribute.String("pocketbase.collection", op.Collection))
+		}
+
+		ctx, span := t.tracer.Start(req.Context(), "pocketbase."+name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("url.path", req.URL.Path),
+			),
+		)
+		defer span.End()
+		if op.RecordID != "" {
+			span.SetAttributes(attribute.String("pocketbase.record_id", op.RecordID))
+		}
+
+		req = req.Clone(ctx)
+		t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+		start := time.Now()
+		resp, err := next.RoundTrip(req)
+
+		status := 0
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			status = resp.StatusCode
+			span.SetAttributes(attribute.Int("http.response.status_code", status))
+			if status >= http.StatusBadRequest {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+		}
+
+		measured := metric.WithAttributes(append(attrs, attribute.Int("http.response.status_code", status))...)
+		t.requests.Add(ctx, 1, measured)
+		t.duration.Record(ctx, time.Since(start).Seconds(), measured)
+		if op.Name == OperationAuth {
+			t.refreshes.Add(ctx, 1)
+		}
+		return resp, err
+	})
+}
+
+// retried is called by retryTransport before a request is resent.
+func (t *telemetry) retried(req *http.Request, attempt int) {
+	if t == nil {
+		return
+	}
+	op, _ := OperationFromContext(req.Context())
+	t.retries.Add(req.Context(), 1, metric.WithAttributes(attribute.String("pocketbase.operation", op.Name)))
+	trace.SpanFromContext(req.Context()).AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", attempt)))
+}
+
+func (t *telemetry) reconnected() {
+	if t == nil {
+		return
+	}
+	t.reconnects.Add(context.Background(), 1)
+}
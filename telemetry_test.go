@@ -0,0 +1,111 @@
+package pocketbase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTelemetry(t *testing.T) {
+	var (
+		listCalls   atomic.Int32
+		traceparent atomic.Value
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent.Store(r.Header.Get("traceparent"))
+		switch r.URL.Path {
+		case "/api/admins/auth-with-password":
+			_, _ = w.Write([]byte(`{"token": "token"}`))
+		case "/api/collections/posts/records":
+			if listCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"page": 1, "items": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	spans := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	client := NewClient(server.URL,
+		WithAdminEmailPassword("admin@admin.com", "secret"),
+		WithRetryPolicy(RetryPolicy{
+			MaxRetries: 1,
+			NewBackOff: func() backoff.BackOff { return &backoff.ZeroBackOff{} },
+		}),
+		WithTelemetry(TelemetryOptions{
+			TracerProvider: tracerProvider,
+			MeterProvider:  meterProvider,
+			Propagator:     propagation.TraceContext{},
+		}),
+	)
+
+	ctx, parent := tracerProvider.Tracer("test").Start(context.Background(), "parent")
+	_, err := client.WithContext(ctx).List("posts", ParamsList{})
+	require.NoError(t, err)
+	_, err = CollectionSet[map[string]any](client.WithContext(ctx), "posts").One("missing")
+	require.Error(t, err)
+	parent.End()
+
+	got := spans.GetSpans()
+	require.Len(t, got, 4)
+	auth, list, one := got[0], got[1], got[2]
+
+	assert.Equal(t, "pocketbase.auth", auth.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), auth.Parent.SpanID(), "authorization runs in the context of the triggering call")
+
+	assert.Equal(t, "pocketbase.list", list.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), list.Parent.SpanID())
+	assert.Contains(t, list.Attributes, attribute.String("pocketbase.collection", "posts"))
+	assert.Contains(t, list.Attributes, attribute.Int("http.response.status_code", http.StatusOK))
+	require.Len(t, list.Events, 1)
+	assert.Equal(t, "retry", list.Events[0].Name)
+
+	assert.Equal(t, "pocketbase.one", one.Name)
+	assert.Contains(t, one.Attributes, attribute.String("pocketbase.record_id", "missing"))
+	assert.Equal(t, codes.Error, one.Status.Code)
+	assert.Contains(t, traceparent.Load(), one.SpanContext.TraceID().String())
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+	sums := map[string]int64{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, point := range sum.DataPoints {
+					sums[m.Name] += point.Value
+				}
+			}
+			if histogram, ok := m.Data.(metricdata.Histogram[float64]); ok {
+				for _, point := range histogram.DataPoints {
+					sums[m.Name] += int64(point.Count)
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		"pocketbase.client.requests":         3,
+		"pocketbase.client.request.duration": 3,
+		"pocketbase.client.retries":          1,
+		"pocketbase.client.auth.refreshes":   1,
+	}, sums)
+}
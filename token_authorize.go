@@ -1,7 +1,9 @@
 package pocketbase
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -9,11 +11,14 @@ import (
 )
 
 type authorizeToken struct {
+	authListeners
 	client      *resty.Client
 	url         string
-	token       string
-	tokenValid  time.Time
 	tokenSingle singleflight.Group
+
+	mu         sync.RWMutex
+	token      string
+	tokenValid time.Time
 }
 
 func newAuthorizeToken(c *resty.Client, url string, token string) authStore {
@@ -26,17 +31,21 @@ func newAuthorizeToken(c *resty.Client, url string, token string) authStore {
 	}
 }
 
-func (a *authorizeToken) authorize() error {
+func (a *authorizeToken) authorize(ctx context.Context) error {
 	type authResponse struct {
 		Token string `json:"token"`
 	}
-	_, err, _ := a.tokenSingle.Do("auth-refresh", func() (interface{}, error) {
-		if time.Now().Before(a.tokenValid) {
+	if a.IsValid() {
+		return nil
+	}
+	results := a.tokenSingle.DoChan("auth-refresh", func() (interface{}, error) {
+		if a.IsValid() {
 			return nil, nil
 		}
 		resp, err := a.client.R().
+			SetContext(withOperation(context.WithoutCancel(ctx), Operation{Name: OperationAuth})).
 			SetHeader("Content-Type", "application/json").
-			SetHeader("Authorization", a.token).
+			SetHeader("Authorization", a.Token()).
 			SetResult(&authResponse{}).
 			Post(a.url)
 		if err != nil {
@@ -50,18 +59,25 @@ func (a *authorizeToken) authorize() error {
 			)
 		}
 		auth := *resp.Result().(*authResponse)
+		a.mu.Lock()
 		a.token = auth.Token
-		a.client.SetHeader("Authorization", auth.Token)
 		a.tokenValid = time.Now().Add(60 * time.Minute)
+		a.mu.Unlock()
+		a.client.SetHeader("Authorization", auth.Token)
+		a.notify(auth.Token)
 		return nil, nil
 	})
-	return err
+	return awaitAuth(ctx, results)
 }
 
 func (a *authorizeToken) IsValid() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return time.Now().Before(a.tokenValid)
 }
 
 func (a *authorizeToken) Token() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.token
 }
@@ -0,0 +1,79 @@
+package pocketbase
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/donovanhide/eventsource"
+)
+
+// Message is a realtime message published to a custom (non-collection) topic,
+// e.g. by server code sending subscriptions.Message through the PocketBase broker.
+type Message[T any] struct {
+	// Topic is the SSE event name, i.e. the subscriptions.Message name.
+	Topic string
+	Data  T
+	Raw   json.RawMessage
+	Error error
+
+	decode func(data []byte, v any) error
+}
+
+// Decode unmarshals the raw payload into v with the client decoder,
+// useful with SubscribeRaw streams carrying different payload types.
+func (m Message[T]) Decode(v any) error {
+	if m.decode == nil {
+		return json.Unmarshal(m.Raw, v)
+	}
+	return m.decode(m.Raw, v)
+}
+
+type TopicStream[T any] struct {
+	*realtimeStream[Message[T]]
+}
+
+func (s *TopicStream[T]) Messages() <-chan Message[T] {
+	return s.channel.Listen().C
+}
+
+// SubscribeRaw subscribes to custom topics and delivers their payloads undecoded.
+func (c *Client) SubscribeRaw(topics ...string) (*TopicStream[json.RawMessage], error) {
+	opts := SubscribeOptions{
+		ReconnectStrategy: &backoff.ZeroBackOff{},
+	}
+	return SubscribeTopics[json.RawMessage](c, opts, topics...)
+}
+
+// SubscribeTopics subscribes to custom topics and decodes every payload into T.
+func SubscribeTopics[T any](c *Client, opts SubscribeOptions, topics ...string) (*TopicStream[T], error) {
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("[realtime] at least one topic is required")
+	}
+
+	stream := &TopicStream[T]{newRealtimeStream[Message[T]]()}
+
+	handleSSEEvent := func(ev eventsource.Event) {
+		m := Message[T]{
+			Topic:  ev.Event(),
+			Raw:    json.RawMessage(ev.Data()),
+			decode: c.decode,
+		}
+		if raw, ok := any(&m.Data).(*json.RawMessage); ok {
+			// payload may be any bytes, not necessarily JSON
+			*raw = m.Raw
+		} else if err := c.decode(m.Raw, &m.Data); err != nil {
+			if opts.SeparateErrors {
+				stream.publishError(fmt.Errorf("[realtime] can't unmarshal %s message, err %w", m.Topic, err))
+				return
+			}
+			m.Error = err
+		}
+		stream.publish(m)
+	}
+
+	if err := startRealtime(c, stream.realtimeStream, opts, topics, handleSSEEvent); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
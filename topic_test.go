@@ -0,0 +1,71 @@
+package pocketbase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Collection names are topics as well, so record changes are used as the published messages.
+
+func TestClient_SubscribeRaw(t *testing.T) {
+	client := NewClient(defaultURL)
+	stream, err := client.SubscribeRaw(migrations.PostsPublic)
+	require.NoError(t, err)
+	defer stream.Unsubscribe()
+	<-stream.Ready()
+
+	ch := stream.Messages()
+
+	resp, err := client.Create(migrations.PostsPublic, map[string]any{
+		"field": "value_" + time.Now().Format(time.StampMilli),
+	})
+	require.NoError(t, err)
+
+	select {
+	case m := <-ch:
+		assert.Equal(t, migrations.PostsPublic, m.Topic)
+		assert.NoError(t, m.Error)
+		assert.Equal(t, m.Raw, m.Data)
+
+		var e Event[map[string]any]
+		require.NoError(t, m.Decode(&e))
+		assert.Equal(t, resp.ID, e.Record["id"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+}
+
+func TestSubscribeTopics(t *testing.T) {
+	type payload struct {
+		Action string `json:"action"`
+	}
+
+	client := NewClient(defaultURL)
+	// the zero options reconnect immediately
+	stream, err := SubscribeTopics[payload](client, SubscribeOptions{}, migrations.PostsPublic)
+	require.NoError(t, err)
+	defer stream.Unsubscribe()
+	<-stream.Ready()
+
+	ch := stream.Messages()
+
+	_, err = client.Create(migrations.PostsPublic, map[string]any{
+		"field": "value_" + time.Now().Format(time.StampMilli),
+	})
+	require.NoError(t, err)
+
+	select {
+	case m := <-ch:
+		assert.Equal(t, migrations.PostsPublic, m.Topic)
+		assert.Equal(t, "create", m.Data.Action)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+
+	_, err = SubscribeTopics[payload](client, SubscribeOptions{})
+	assert.Error(t, err)
+}
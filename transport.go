@@ -0,0 +1,179 @@
+package pocketbase
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// WithHTTPClient sends requests with the transport, timeout, cookie jar and redirect policy of client.
+// client.Timeout is applied like WithTimeout, so it doesn't cut realtime connections.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		if client.Timeout > 0 {
+			c.timeout = client.Timeout
+		}
+		hc := c.client.GetClient()
+		hc.Jar = client.Jar
+		hc.CheckRedirect = client.CheckRedirect
+		c.transport = client.Transport
+		if c.transport == nil {
+			c.transport = http.DefaultTransport
+		}
+	}
+}
+
+// WithTransport sends requests with transport, retries and rate limits wrap it.
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithHeader adds a header to every request.
+func WithHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		c.client.SetHeader(key, value)
+	}
+}
+
+func WithUserAgent(userAgent string) ClientOption {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithTimeout limits each attempt of a request, including reading the response, to timeout;
+// retries and their waits are not included. Realtime connections are long-lived and not limited.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithProxy sends requests through the proxy at proxyURL, e.g. "http://proxy:3128".
+// The transport options are applied to *http.Transport only and ignored for other round trippers.
+func WithProxy(proxyURL *url.URL) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		t.Proxy = http.ProxyURL(proxyURL)
+	})
+}
+
+// WithTLSConfig replaces the TLS configuration, e.g. to pin the server certificate.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		t.TLSClientConfig = config.Clone()
+	})
+}
+
+// WithRootCAs trusts the server certificates signed by pool instead of the system roots.
+func WithRootCAs(pool *x509.CertPool) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		tlsConfig(t).RootCAs = pool
+	})
+}
+
+// WithClientCertificate authenticates the client with cert for mutual TLS.
+func WithClientCertificate(cert tls.Certificate) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		config := tlsConfig(t)
+		config.Certificates = append(config.Certificates, cert)
+	})
+}
+
+// WithUnixSocket connects to PocketBase listening on the Unix socket at path, e.g. a sidecar proxy.
+// The host of the client url is then only used for the Host header.
+func WithUnixSocket(path string) ClientOption {
+	return withTransportOption(func(t *http.Transport) {
+		dialer := &net.Dialer{Timeout: 30 * time.Second}
+		t.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", path)
+		}
+		t.Proxy = nil
+	})
+}
+
+func withTransportOption(fn func(*http.Transport)) ClientOption {
+	return func(c *Client) {
+		c.transportOptions = append(c.transportOptions, fn)
+	}
+}
+
+func tlsConfig(t *http.Transport) *tls.Config {
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return t.TLSClientConfig
+}
+
+// buildTransport returns the base transport with transport options applied,
+// wrapped with limits, retries, logging, middlewares and telemetry.
+func (c *Client) buildTransport() http.RoundTripper {
+	transport := c.transport
+	if len(c.transportOptions) > 0 {
+		if t, ok := transport.(*http.Transport); ok {
+			t = t.Clone()
+			for _, opt := range c.transportOptions {
+				opt(t)
+			}
+			transport = t
+		} else {
+			c.logger.Warn("transport options ignored, transport is not *http.Transport", "transport", fmt.Sprintf("%T", transport))
+		}
+	}
+	if c.timeout > 0 {
+		transport = &timeoutTransport{base: transport, timeout: c.timeout}
+	}
+	if c.limiter != nil {
+		transport = &limitTransport{base: transport, limiter: c.limiter}
+	}
+	transport = &retryTransport{base: transport, policy: c.retry, telemetry: c.telemetry, logger: c.logger}
+	transport = c.wrapMiddlewares(&logTransport{base: transport, logger: c.logger})
+	if c.telemetry != nil {
+		transport = c.telemetry.middleware(transport)
+	}
+	return transport
+}
+
+type streamingKey struct{}
+
+// withStreaming marks requests of long-lived connections, which have no timeout.
+func withStreaming(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamingKey{}, true)
+}
+
+// timeoutTransport limits a single attempt, the timeout ends when the response body is closed.
+type timeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if streaming, _ := req.Context().Value(streamingKey{}).(bool); streaming {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,201 @@
+package pocketbase
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const emptyList = `{"page": 1, "items": []}`
+
+func TestWithHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "pocketbase-test/1.0", r.UserAgent())
+		assert.Equal(t, "tenant-a", r.Header.Get("X-Tenant"))
+		_, _ = w.Write([]byte(emptyList))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithUserAgent("pocketbase-test/1.0"), WithHeader("X-Tenant", "tenant-a"))
+	_, err := client.List("posts", ParamsList{})
+	assert.NoError(t, err)
+}
+
+func TestWithTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(emptyList))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		option func(http.RoundTripper) ClientOption
+	}{
+		{
+			name:   "Transport",
+			option: WithTransport,
+		},
+		{
+			name: "HTTP client",
+			option: func(rt http.RoundTripper) ClientOption {
+				return WithHTTPClient(&http.Client{Transport: rt})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			transport := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls.Add(1)
+				return http.DefaultTransport.RoundTrip(req)
+			})
+
+			client := NewClient(server.URL, tt.option(transport))
+			_, err := client.List("posts", ParamsList{})
+			assert.NoError(t, err)
+			assert.Equal(t, int32(1), calls.Load())
+		})
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(emptyList))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithTimeout(50*time.Millisecond), WithRetryPolicy(RetryPolicy{}))
+	_, err := client.List("posts", ParamsList{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// realtime connections are not limited
+	transport := &timeoutTransport{base: http.DefaultTransport, timeout: 50 * time.Millisecond}
+	req, err := http.NewRequestWithContext(withStreaming(context.Background()), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+}
+
+func TestWithHTTPClient_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/realtime" && r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/realtime":
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("event: PB_CONNECT\ndata: {\"clientId\": \"abc\"}\n\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte("event: news\ndata: {\"title\": \"late\"}\n\n"))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			time.Sleep(200 * time.Millisecond)
+			_, _ = w.Write([]byte(emptyList))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		option ClientOption
+	}{
+		{
+			name:   "Timeout",
+			option: WithTimeout(50 * time.Millisecond),
+		},
+		{
+			name:   "HTTP client",
+			option: WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(server.URL, tt.option, WithRetryPolicy(RetryPolicy{}))
+			_, err := client.List("posts", ParamsList{})
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+			// realtime connections outlive the timeout
+			stream, err := client.SubscribeRaw("news")
+			require.NoError(t, err)
+			defer stream.Unsubscribe()
+			select {
+			case m := <-stream.Messages():
+				assert.JSONEq(t, `{"title": "late"}`, string(m.Data))
+			case <-time.After(2 * time.Second):
+				t.Fatal("realtime connection was cut")
+			}
+		})
+	}
+}
+
+func TestWithProxy(t *testing.T) {
+	var host atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host.Store(r.URL.Host)
+		_, _ = w.Write([]byte(emptyList))
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	client := NewClient("http://pocketbase.internal:8090", WithProxy(proxyURL))
+	_, err = client.List("posts", ParamsList{})
+	assert.NoError(t, err)
+	assert.Equal(t, "pocketbase.internal:8090", host.Load())
+}
+
+func TestWithUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "pb.sock")
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	server := &httptest.Server{
+		Listener: listener,
+		Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "pocketbase", r.Host)
+			_, _ = w.Write([]byte(emptyList))
+		})},
+	}
+	server.Start()
+	defer server.Close()
+
+	client := NewClient("http://pocketbase", WithUnixSocket(socket))
+	_, err = client.List("posts", ParamsList{})
+	assert.NoError(t, err)
+}
+
+func TestWithRootCAs(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(emptyList))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	noRetry := WithRetryPolicy(RetryPolicy{})
+
+	_, err := NewClient(server.URL, noRetry).List("posts", ParamsList{})
+	assert.Error(t, err, "unknown authority")
+
+	_, err = NewClient(server.URL, noRetry, WithRootCAs(pool)).List("posts", ParamsList{})
+	assert.Error(t, err, "no client certificate")
+
+	client := NewClient(server.URL, noRetry, WithRootCAs(pool), WithClientCertificate(server.TLS.Certificates[0]))
+	_, err = client.List("posts", ParamsList{})
+	assert.NoError(t, err)
+}
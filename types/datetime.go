@@ -0,0 +1,89 @@
+// Package types contains Go types for PocketBase field values.
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultDateLayout is the format used by PocketBase for date fields and the created/updated timestamps.
+const DefaultDateLayout = "2006-01-02 15:04:05.000Z"
+
+// DateTime is a time.Time in PocketBase's date format, empty strings decode to the zero value.
+type DateTime struct {
+	t time.Time
+}
+
+func NowDateTime() DateTime {
+	return DateTime{t: time.Now().UTC()}
+}
+
+func NewDateTime(t time.Time) DateTime {
+	return DateTime{t: t.UTC()}
+}
+
+// ParseDateTime parses PocketBase's date format, RFC 3339 and the date only layout.
+func ParseDateTime(value string) (DateTime, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return DateTime{}, nil
+	}
+	for _, layout := range []string{DefaultDateLayout, "2006-01-02 15:04:05Z07:00", time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return NewDateTime(t), nil
+		}
+	}
+	return DateTime{}, fmt.Errorf("invalid date %q", value)
+}
+
+func (d DateTime) Time() time.Time {
+	return d.t
+}
+
+func (d DateTime) IsZero() bool {
+	return d.t.IsZero()
+}
+
+// String returns the date in DefaultDateLayout, or an empty string for the zero value.
+func (d DateTime) String() string {
+	if d.t.IsZero() {
+		return ""
+	}
+	return d.t.UTC().Format(DefaultDateLayout)
+}
+
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = DateTime{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseDateTime(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+func (d DateTime) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *DateTime) UnmarshalText(data []byte) error {
+	parsed, err := ParseDateTime(string(data))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
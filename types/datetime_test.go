@@ -0,0 +1,54 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateTime_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "PocketBase format", json: `"2022-11-12 23:43:15.123Z"`, want: time.Date(2022, 11, 12, 23, 43, 15, 123e6, time.UTC)},
+		{name: "RFC 3339", json: `"2022-11-12T23:43:15+01:00"`, want: time.Date(2022, 11, 12, 22, 43, 15, 0, time.UTC)},
+		{name: "Date only", json: `"2022-11-12"`, want: time.Date(2022, 11, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "Empty string", json: `""`},
+		{name: "Null", json: `null`},
+		{name: "Invalid", json: `"yesterday"`, wantErr: true},
+		{name: "Not a string", json: `12`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d DateTime
+			err := json.Unmarshal([]byte(tt.json), &d)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.True(t, tt.want.Equal(d.Time()), d.Time())
+		})
+	}
+}
+
+func TestDateTime_MarshalJSON(t *testing.T) {
+	type record struct {
+		Created DateTime `json:"created"`
+	}
+
+	data, err := json.Marshal(record{Created: NewDateTime(time.Date(2022, 11, 12, 23, 43, 15, 123e6, time.UTC))})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"created": "2022-11-12 23:43:15.123Z"}`, string(data))
+
+	data, err = json.Marshal(record{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"created": ""}`, string(data))
+
+	var r record
+	require.NoError(t, json.Unmarshal(data, &r))
+	assert.True(t, r.Created.IsZero())
+	assert.Equal(t, "", r.Created.String())
+}
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+)
+
+// Strings is a multiple select or relation value. PocketBase returns a plain string
+// for such fields when they were single value before a schema change, so both forms are accepted.
+type Strings []string
+
+func (s Strings) MarshalJSON() ([]byte, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]string(s))
+}
+
+func (s *Strings) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*s = nil
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var value string
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		*s = nil
+		if value != "" {
+			*s = Strings{value}
+		}
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*s = values
+	return nil
+}
+
+// Files is a file field value, a list of file names in either single or multiple form.
+type Files []string
+
+func (f Files) MarshalJSON() ([]byte, error) {
+	return Strings(f).MarshalJSON()
+}
+
+func (f *Files) UnmarshalJSON(data []byte) error {
+	return (*Strings)(f).UnmarshalJSON(data)
+}
+
+// URLs returns the download urls of the files of the given record.
+func (f Files) URLs(baseURL, collection, recordID string) []string {
+	urls := make([]string, 0, len(f))
+	for _, name := range f {
+		urls = append(urls, FileURL(baseURL, collection, recordID, name))
+	}
+	return urls
+}
+
+func FileURL(baseURL, collection, recordID, name string) string {
+	return baseURL + "/api/files/" + url.PathEscape(collection) + "/" + url.PathEscape(recordID) + "/" + url.PathEscape(name)
+}
+
+// JSON is a json field value decoded into T. Valid is false when the field is null or empty.
+type JSON[T any] struct {
+	Value T
+	Valid bool
+}
+
+func NewJSON[T any](value T) JSON[T] {
+	return JSON[T]{Value: value, Valid: true}
+}
+
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	if !j.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(j.Value)
+}
+
+func (j *JSON[T]) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) || bytes.Equal(data, []byte(`""`)) {
+		*j = JSON[T]{}
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*j = JSON[T]{Value: value, Valid: true}
+	return nil
+}
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrings_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    Strings
+		wantErr bool
+	}{
+		{json: `["a", "b"]`, want: Strings{"a", "b"}},
+		{json: `"a"`, want: Strings{"a"}},
+		{json: `""`, want: nil},
+		{json: `null`, want: nil},
+		{json: `[]`, want: Strings{}},
+		{json: `12`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var s Strings
+			err := json.Unmarshal([]byte(tt.json), &s)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, s)
+		})
+	}
+
+	data, err := json.Marshal(Strings(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "[]", string(data))
+}
+
+func TestFiles(t *testing.T) {
+	var f Files
+	require.NoError(t, json.Unmarshal([]byte(`"avatar_x1.png"`), &f))
+	assert.Equal(t, Files{"avatar_x1.png"}, f)
+	assert.Equal(t,
+		[]string{"http://127.0.0.1:8090/api/files/users/abc/avatar_x1.png"},
+		f.URLs("http://127.0.0.1:8090", "users", "abc"),
+	)
+}
+
+func TestJSON(t *testing.T) {
+	type meta struct {
+		Source string `json:"source"`
+	}
+
+	var j JSON[meta]
+	require.NoError(t, json.Unmarshal([]byte(`{"source": "import"}`), &j))
+	assert.True(t, j.Valid)
+	assert.Equal(t, "import", j.Value.Source)
+
+	require.NoError(t, json.Unmarshal([]byte(`""`), &j))
+	assert.False(t, j.Valid)
+
+	data, err := json.Marshal(j)
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+
+	data, err = json.Marshal(NewJSON(meta{Source: "api"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"source": "api"}`, string(data))
+}
+
+func TestGeoPoint(t *testing.T) {
+	tests := []struct {
+		json    string
+		want    GeoPoint
+		wantErr bool
+	}{
+		{json: `{"lon": 21.0122, "lat": 52.2297}`, want: GeoPoint{Lon: 21.0122, Lat: 52.2297}},
+		{json: `{"lon": 0, "lat": 0}`, want: GeoPoint{}},
+		{json: `null`, want: GeoPoint{}},
+		{json: `""`, want: GeoPoint{}},
+		{json: `[21, 52]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			var p GeoPoint
+			err := json.Unmarshal([]byte(tt.json), &p)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, p)
+		})
+	}
+
+	data, err := json.Marshal(GeoPoint{Lon: 21.0122, Lat: 52.2297})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"lon": 21.0122, "lat": 52.2297}`, string(data))
+
+	warsaw, krakow := GeoPoint{Lon: 21.0122, Lat: 52.2297}, GeoPoint{Lon: 19.9450, Lat: 50.0647}
+	assert.InDelta(t, 252000, warsaw.Distance(krakow), 2000)
+	assert.True(t, GeoPoint{}.IsZero())
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+)
+
+const earthRadius = 6371008.8 // meters
+
+// GeoPoint is a geoPoint field value, stored by PocketBase as {"lon": ..., "lat": ...}.
+// An empty or null value decodes to the zero point, which is also the field default.
+type GeoPoint struct {
+	Lon float64 `json:"lon"`
+	Lat float64 `json:"lat"`
+}
+
+func (p GeoPoint) IsZero() bool {
+	return p.Lon == 0 && p.Lat == 0
+}
+
+func (p *GeoPoint) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) || bytes.Equal(data, []byte(`""`)) {
+		*p = GeoPoint{}
+		return nil
+	}
+	type point GeoPoint
+	var value point
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*p = GeoPoint(value)
+	return nil
+}
+
+// Distance returns the great-circle distance to other in meters (the geoDistance filter function uses kilometers).
+func (p GeoPoint) Distance(other GeoPoint) float64 {
+	lat1, lat2 := p.Lat*math.Pi/180, other.Lat*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (other.Lon - p.Lon) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
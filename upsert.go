@@ -0,0 +1,64 @@
+package pocketbase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/duke-git/lancet/v2/convertor"
+)
+
+var ErrUpsertAmbiguous = errors.New("upsert filter matches more than one record")
+
+type UpsertOptions struct {
+	// ExpectedUpdated, if set, is the updated timestamp the matched record must still have,
+	// e.g. from a previous read; the upsert fails with ErrConflict otherwise.
+	ExpectedUpdated string
+}
+
+type UpsertResult struct {
+	ID       string
+	Inserted bool
+}
+
+// FilterEq builds a filter matching records with field equal to value, quoting and escaping value.
+func FilterEq(field string, value any) string {
+	v := strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(convertor.ToString(value))
+	return field + "='" + v + "'"
+}
+
+// Upsert looks up a single record by filter (e.g. FilterEq("external_id", id)),
+// patches it with body if found or creates it otherwise.
+func (c Collection[T]) Upsert(filter string, body T) (UpsertResult, error) {
+	return c.UpsertWith(filter, body, UpsertOptions{})
+}
+
+func (c Collection[T]) UpsertWith(filter string, body T, opts UpsertOptions) (UpsertResult, error) {
+	var result UpsertResult
+
+	existing, err := listAs[Record](c.Client, c.Name, ParamsList{Page: 1, Size: 2, Filters: filter})
+	if err != nil {
+		return result, fmt.Errorf("[upsert] can't look up record, err %w", err)
+	}
+
+	switch len(existing.Items) {
+	case 0:
+		created, err := c.Create(body)
+		if err != nil {
+			return result, err
+		}
+		return UpsertResult{ID: created.ID, Inserted: true}, nil
+	case 1:
+	default:
+		return result, fmt.Errorf("[upsert] filter %s, err %w", filter, ErrUpsertAmbiguous)
+	}
+
+	result.ID = existing.Items[0].ID()
+	if opts.ExpectedUpdated != "" {
+		if updated := existing.Items[0].GetString("updated"); updated != opts.ExpectedUpdated {
+			return result, &ConflictError{ID: result.ID, Field: "updated", Expected: opts.ExpectedUpdated, Actual: updated}
+		}
+	}
+
+	return result, c.Update(result.ID, body)
+}
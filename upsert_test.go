@@ -0,0 +1,53 @@
+package pocketbase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/r--w/pocketbase/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterEq(t *testing.T) {
+	assert.Equal(t, "field='value'", FilterEq("field", "value"))
+	assert.Equal(t, `field='it\'s'`, FilterEq("field", "it's"))
+	assert.Equal(t, "count='5'", FilterEq("count", 5))
+}
+
+func TestCollection_Upsert(t *testing.T) {
+	client := NewClient(defaultURL)
+	field := "value_" + time.Now().Format(time.StampMilli) + "_it's"
+	collection := Collection[map[string]any]{client, migrations.PostsPublic}
+
+	// first upsert inserts
+	inserted, err := collection.Upsert(FilterEq("field", field), map[string]any{"field": field})
+	require.NoError(t, err)
+	assert.True(t, inserted.Inserted)
+	assert.NotEmpty(t, inserted.ID)
+
+	// second upsert patches the same record
+	updated, err := collection.Upsert(FilterEq("field", field), map[string]any{"field": field})
+	require.NoError(t, err)
+	assert.False(t, updated.Inserted)
+	assert.Equal(t, inserted.ID, updated.ID)
+
+	// stale updated timestamp is a conflict
+	_, err = collection.UpsertWith(FilterEq("field", field), map[string]any{"field": field}, UpsertOptions{
+		ExpectedUpdated: "2000-01-01 00:00:00.000Z",
+	})
+	assert.ErrorIs(t, err, ErrConflict)
+
+	item, err := collection.One(inserted.ID)
+	require.NoError(t, err)
+	_, err = collection.UpsertWith(FilterEq("field", field), map[string]any{"field": field}, UpsertOptions{
+		ExpectedUpdated: item["updated"].(string),
+	})
+	assert.NoError(t, err)
+
+	// more matches are ambiguous
+	_, err = collection.Create(map[string]any{"field": field})
+	require.NoError(t, err)
+	_, err = collection.Upsert(FilterEq("field", field), map[string]any{"field": field})
+	assert.ErrorIs(t, err, ErrUpsertAmbiguous)
+}